@@ -3,13 +3,19 @@ package main
 import (
 	"fmt"
 	"log"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/handler"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/mailer"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/middleware"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/notifier"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -22,31 +28,188 @@ func main() {
 		log.Fatal("Failed to load configuration:", err)
 	}
 
+	// The default secret is public; refuse to sign production tokens with it
+	if cfg.Server.Mode == gin.ReleaseMode && cfg.JWT.Algorithm == service.AlgorithmHS256 && cfg.JWT.Secret == "your-secret-key" {
+		log.Fatal("JWT_SECRET must be set in release mode")
+	}
+	// Likewise for the key that encrypts two-factor secrets and signing keys at rest
+	if cfg.Server.Mode == gin.ReleaseMode && cfg.Auth.EncryptionKey == "your-encryption-key" {
+		log.Fatal("ENCRYPTION_KEY must be set in release mode")
+	}
+
 	// Initialize database
 	db, err := initDatabase(cfg)
 	if err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 
+	// Accounts created before email verification existed were never asked to verify;
+	// treat them as verified once the column is added
+	backfillEmailVerified := db.Migrator().HasTable(&model.User{}) && !db.Migrator().HasColumn(&model.User{}, "EmailVerifiedAt")
+
 	// Auto migrate database schema
-	if err := db.AutoMigrate(&model.User{}, &model.Todo{}); err != nil {
+	if err := db.AutoMigrate(
+		&model.User{},
+		&model.Todo{},
+		&model.TodoItem{},
+		&model.Tag{},
+		&model.Project{},
+		&model.Reminder{},
+		&model.RefreshToken{},
+		&model.RevokedToken{},
+		&model.UserTokenVersion{},
+		&model.OneTimeToken{},
+		&model.MFARecoveryCode{},
+		&model.LoginAttempt{},
+		&model.SigningKey{},
+		&model.PersonalAccessToken{},
+		&model.OIDCLoginState{},
+		&model.ExternalIdentity{},
+		&model.Session{},
+		&model.AuditLog{},
+		&model.WebAuthnChallenge{},
+		&model.WebAuthnCredential{},
+		&model.DeviceAuthorization{},
+	); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	if backfillEmailVerified {
+		if err := db.Unscoped().Model(&model.User{}).Where("email_verified_at IS NULL").UpdateColumn("email_verified_at", gorm.Expr("created_at")).Error; err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+	}
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	todoRepo := repository.NewTodoRepository(db)
+	todoItemRepo := repository.NewTodoItemRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	projectRepo := repository.NewProjectRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	revocationStore := initRevocationStore(cfg, db)
+	oneTimeTokenRepo := repository.NewOneTimeTokenRepository(db)
+	recoveryCodeRepo := repository.NewMFARecoveryCodeRepository(db)
+	loginAttemptStore := initLoginAttemptStore(cfg, db)
+	signingKeyRepo := repository.NewSigningKeyRepository(db)
+	patRepo := repository.NewPersonalAccessTokenRepository(db)
+	oidcRepo := repository.NewOIDCRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	webauthnRepo := repository.NewWebAuthnRepository(db)
+	deviceRepo := repository.NewDeviceAuthorizationRepository(db)
+
+	// Promote configured administrators
+	if err := userRepo.SetRoleByEmails(cfg.Auth.AdminEmails, model.RoleAdmin); err != nil {
+		log.Fatal("Failed to promote administrators:", err)
+	}
+
+	// Initialize mailer
+	mail, err := initMailer(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize mailer:", err)
+	}
+
+	// Initialize reminder channels
+	reminderNotifier, err := initNotifier(cfg, mail)
+	if err != nil {
+		log.Fatal("Failed to initialize reminder channels:", err)
+	}
+
+	// Initialize signing keys
+	keyManager, err := service.NewKeyManager(signingKeyRepo, cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize signing keys:", err)
+	}
+	keyManager.StartRotation()
+
+	// Initialize password hashing
+	passwordHasher, err := initPasswordHasher(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize password hashing:", err)
+	}
+
+	// Initialize password policy
+	var breachedPasswords security.BreachedPasswords
+	if cfg.Auth.BreachedPasswordsPath != "" {
+		breachedPasswords, err = security.LoadBreachedPasswords(cfg.Auth.BreachedPasswordsPath)
+		if err != nil {
+			log.Fatal("Failed to load breached password list:", err)
+		}
+	}
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, cfg)
-	todoService := service.NewTodoService(todoRepo)
+	passwordPolicy := service.NewPasswordPolicy(breachedPasswords, cfg)
+	loginThrottler := service.NewLoginThrottler(loginAttemptStore, cfg)
+	magicLinkLimiter := service.NewRateLimiter(loginAttemptStore, "magic_link", cfg.Auth.MagicLinkRequestsPerHour, time.Hour)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, revocationStore, oneTimeTokenRepo, patRepo, sessionRepo, mail, loginThrottler, magicLinkLimiter, passwordHasher, passwordPolicy, keyManager, cfg)
+	mfaService := service.NewMFAService(userRepo, recoveryCodeRepo, sessionRepo, authService, loginThrottler, passwordHasher, cfg)
+	tokenService := service.NewTokenService(patRepo, userRepo)
+	sessionService := service.NewSessionService(sessionRepo, refreshTokenRepo)
+	accountService := service.NewAccountService(userRepo, sessionRepo, authService, loginThrottler, passwordHasher, passwordPolicy, cfg)
+	accountService.StartPurge()
+	todoService := service.NewTodoService(todoRepo, tagRepo, projectRepo, reminderRepo)
+	tagService := service.NewTagService(tagRepo)
+	projectService := service.NewProjectService(projectRepo)
+	todoItemService := service.NewTodoItemService(todoItemRepo, todoService)
+	reminderService := service.NewReminderService(reminderRepo, todoService, reminderNotifier, cfg)
+	reminderService.StartScheduler()
+	adminService := service.NewAdminService(userRepo, auditLogRepo, authService, todoService)
+	webauthnService := service.NewWebAuthnService(webauthnRepo, userRepo, authService, cfg)
+	deviceApprovalLimiter := service.NewRateLimiter(loginAttemptStore, "device_approval", cfg.RateLimit.DeviceApprovalAttempts, time.Duration(cfg.RateLimit.DeviceApprovalWindowMinutes)*time.Minute)
+	deviceService := service.NewDeviceService(deviceRepo, userRepo, authService, deviceApprovalLimiter, cfg)
+
+	// Header auth mode for deployments behind an authenticating reverse proxy
+	proxyAuth, err := middleware.NewProxyAuth(service.NewProxyAuthService(userRepo, cfg), cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize proxy auth:", err)
+	}
+
+	// Cookie auth mode for browser clients
+	cookies := middleware.NewTokenCookies(cfg)
+	if cookies.Enabled() && slices.Contains(cfg.CORS.AllowOrigins, "*") {
+		log.Println("Warning: cookie auth mode is enabled with wildcard CORS origins; cross-origin frontends must be listed explicitly")
+	}
 
 	// Initialize handlers
-	authHandler := handler.NewAuthHandler(authService)
-	todoHandler := handler.NewTodoHandler(todoService)
+	handlers := &routeHandlers{
+		auth:     handler.NewAuthHandler(authService, cookies),
+		account:  handler.NewAccountHandler(accountService, cookies),
+		mfa:      handler.NewMFAHandler(mfaService, cookies),
+		session:  handler.NewSessionHandler(sessionService),
+		webauthn: handler.NewWebAuthnHandler(webauthnService, cookies),
+		device:   handler.NewDeviceHandler(deviceService),
+		jwks:     handler.NewJWKSHandler(keyManager),
+		token:    handler.NewTokenHandler(tokenService),
+		todo:     handler.NewTodoHandler(todoService),
+		todoItem: handler.NewTodoItemHandler(todoItemService),
+		reminder: handler.NewReminderHandler(reminderService),
+		tag:      handler.NewTagHandler(tagService),
+		project:  handler.NewProjectHandler(projectService),
+		admin:    handler.NewAdminHandler(adminService),
+	}
+
+	// Single sign-on is only served when configured
+	if cfg.OIDC.Enabled {
+		if cfg.OIDC.Issuer == "" || cfg.OIDC.ClientID == "" {
+			log.Fatal("OIDC_ISSUER and OIDC_CLIENT_ID are required when OIDC is enabled")
+		}
+		oidcService := service.NewOIDCService(oidcRepo, userRepo, authService, cfg)
+		handlers.oidc = handler.NewOIDCHandler(oidcService, cookies)
+	}
+
+	// Directory provisioning is only served when configured
+	if cfg.SCIM.Enabled {
+		if len(cfg.SCIM.Token) < 32 {
+			log.Fatal("SCIM_TOKEN must be at least 32 characters when SCIM is enabled")
+		}
+		scimService := service.NewSCIMService(userRepo, authService)
+		handlers.scim = handler.NewSCIMHandler(scimService)
+	}
 
 	// Initialize Gin router
-	router := setupRouter(cfg, authService, authHandler, todoHandler)
+	router := setupRouter(cfg, authService, sessionService, tokenService, cookies, proxyAuth, handlers)
 
 	// Start server
 	address := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -56,6 +219,26 @@ func main() {
 	}
 }
 
+// routeHandlers groups the HTTP handlers served by the router
+type routeHandlers struct {
+	auth     *handler.AuthHandler
+	account  *handler.AccountHandler
+	mfa      *handler.MFAHandler
+	session  *handler.SessionHandler
+	oidc     *handler.OIDCHandler
+	scim     *handler.SCIMHandler
+	webauthn *handler.WebAuthnHandler
+	device   *handler.DeviceHandler
+	jwks     *handler.JWKSHandler
+	token    *handler.TokenHandler
+	todo     *handler.TodoHandler
+	todoItem *handler.TodoItemHandler
+	reminder *handler.ReminderHandler
+	tag      *handler.TagHandler
+	project  *handler.ProjectHandler
+	admin    *handler.AdminHandler
+}
+
 func initDatabase(cfg *config.Config) (*gorm.DB, error) {
 	dsn := cfg.Database.GetDSN()
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
@@ -66,12 +249,77 @@ func initDatabase(cfg *config.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
-func setupRouter(cfg *config.Config, authService service.AuthService, authHandler *handler.AuthHandler, todoHandler *handler.TodoHandler) *gin.Engine {
+func initRevocationStore(cfg *config.Config, db *gorm.DB) repository.TokenRevocationStore {
+	switch cfg.JWT.RevocationStore {
+	case "postgres":
+		return repository.NewPostgresRevocationStore(db)
+	default:
+		return repository.NewMemoryRevocationStore()
+	}
+}
+
+func initLoginAttemptStore(cfg *config.Config, db *gorm.DB) repository.LoginAttemptStore {
+	switch cfg.Auth.AttemptStore {
+	case "postgres":
+		return repository.NewPostgresLoginAttemptStore(db)
+	default:
+		return repository.NewMemoryLoginAttemptStore()
+	}
+}
+
+func initMailer(cfg *config.Config) (mailer.Mailer, error) {
+	switch cfg.Mail.Driver {
+	case "file":
+		return mailer.NewFileMailer(cfg.Mail.From, cfg.Mail.OutboxDir)
+	case "smtp":
+		return mailer.NewSMTPMailer(cfg.Mail.From, cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword)
+	default:
+		return mailer.NewStdoutMailer(cfg.Mail.From), nil
+	}
+}
+
+func initNotifier(cfg *config.Config, mail mailer.Mailer) (notifier.Notifier, error) {
+	var notifiers []notifier.Notifier
+	for _, channel := range cfg.Reminders.Channels {
+		switch strings.TrimSpace(channel) {
+		case "log":
+			notifiers = append(notifiers, notifier.NewLogNotifier())
+		case "email":
+			notifiers = append(notifiers, notifier.NewEmailNotifier(mail))
+		case "webhook":
+			if cfg.Reminders.WebhookURL == "" {
+				return nil, fmt.Errorf("REMINDER_WEBHOOK_URL is required for the webhook channel")
+			}
+			timeout := time.Duration(cfg.Reminders.WebhookTimeoutSeconds) * time.Second
+			notifiers = append(notifiers, notifier.NewWebhookNotifier(cfg.Reminders.WebhookURL, cfg.Reminders.WebhookSecret, timeout))
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown reminder channel %q", channel)
+		}
+	}
+	return notifier.NewMultiNotifier(notifiers...), nil
+}
+
+func initPasswordHasher(cfg *config.Config) (security.PasswordHasher, error) {
+	params := security.Argon2Params{
+		Memory:      uint32(cfg.Auth.Argon2MemoryKiB),
+		Iterations:  uint32(cfg.Auth.Argon2Iterations),
+		Parallelism: uint8(cfg.Auth.Argon2Parallelism),
+	}
+	return security.NewPasswordHasher(cfg.Auth.PasswordHashAlgorithm, params, cfg.Auth.BcryptCost)
+}
+
+func setupRouter(cfg *config.Config, authService service.AuthService, sessionService service.SessionService, tokenService service.TokenService, cookies *middleware.TokenCookies, proxyAuth *middleware.ProxyAuth, h *routeHandlers) *gin.Engine {
 	// Set Gin mode
 	gin.SetMode(cfg.Server.Mode)
 
 	router := gin.New()
 
+	// Only trust X-Forwarded-For from known proxies so client IPs cannot be spoofed
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies:", err)
+	}
+
 	// Add middlewares
 	router.Use(middleware.LoggingMiddleware())
 	router.Use(middleware.RecoveryMiddleware())
@@ -81,6 +329,9 @@ func setupRouter(cfg *config.Config, authService service.AuthService, authHandle
 		cfg.CORS.AllowHeaders,
 		cfg.CORS.AllowCredentials,
 	))
+	if cookies.Enabled() {
+		router.Use(middleware.CSRFMiddleware())
+	}
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -90,27 +341,168 @@ func setupRouter(cfg *config.Config, authService service.AuthService, authHandle
 		})
 	})
 
+	// Public keys for verifying access tokens
+	router.GET("/.well-known/jwks.json", h.jwks.JWKS)
+
+	// Account routes only accept JWTs; todo routes also accept personal access tokens
+	authMiddleware := middleware.AuthMiddleware(authService, sessionService, nil, cookies, proxyAuth)
+	tokenAuthMiddleware := middleware.AuthMiddleware(authService, sessionService, tokenService, cookies, proxyAuth)
+
 	// API routes
 	api := router.Group("/api/v1")
 
 	// Auth routes
 	auth := api.Group("/auth")
 	{
-		auth.POST("/register", authHandler.Register)
-		auth.POST("/login", authHandler.Login)
-		auth.GET("/me", middleware.AuthMiddleware(authService), authHandler.Me)
+		auth.POST("/register", h.auth.Register)
+		auth.POST("/login", h.auth.Login)
+		auth.POST("/login/2fa", h.mfa.CompleteLogin)
+		auth.POST("/refresh", h.auth.Refresh)
+		auth.GET("/csrf", h.auth.CSRFToken)
+		auth.POST("/forgot-password", h.auth.ForgotPassword)
+		auth.POST("/reset-password", h.auth.ResetPassword)
+		auth.POST("/verify-email", h.auth.VerifyEmail)
+		auth.POST("/magic-link", h.auth.RequestMagicLink)
+		auth.POST("/magic-link/callback", h.auth.MagicLinkLogin)
+		auth.POST("/verify-email/resend", authMiddleware, h.auth.ResendVerification)
+		auth.POST("/logout", authMiddleware, h.auth.Logout)
+		auth.POST("/logout-all", authMiddleware, h.auth.LogoutAll)
+		auth.GET("/me", authMiddleware, h.auth.Me)
+		auth.PATCH("/me", authMiddleware, h.account.UpdateProfile)
+		auth.DELETE("/me", authMiddleware, h.account.DeleteAccount)
+		auth.POST("/me/password", authMiddleware, h.account.ChangePassword)
+	}
+
+	// Single sign-on routes (public)
+	if h.oidc != nil {
+		oidc := auth.Group("/oidc")
+		{
+			oidc.GET("/login", h.oidc.Login)
+			oidc.POST("/callback", h.oidc.Callback)
+		}
+	}
+
+	// Two-factor authentication routes (protected)
+	mfa := auth.Group("/2fa")
+	mfa.Use(authMiddleware)
+	{
+		mfa.POST("/setup", h.mfa.Setup)
+		mfa.POST("/confirm", h.mfa.Confirm)
+		mfa.POST("/disable", h.mfa.Disable)
+	}
+
+	// Passkey routes; logins are public, registration and management are protected
+	passkeys := auth.Group("/webauthn")
+	{
+		passkeys.POST("/login/begin", h.webauthn.BeginLogin)
+		passkeys.POST("/login/finish", h.webauthn.FinishLogin)
+		passkeys.POST("/register/begin", authMiddleware, h.webauthn.BeginRegistration)
+		passkeys.POST("/register/finish", authMiddleware, h.webauthn.FinishRegistration)
+		passkeys.GET("/credentials", authMiddleware, h.webauthn.ListCredentials)
+		passkeys.PATCH("/credentials/:id", authMiddleware, h.webauthn.RenameCredential)
+		passkeys.DELETE("/credentials/:id", authMiddleware, h.webauthn.DeleteCredential)
+	}
+
+	// Device authorization grant; devices poll publicly, users approve while logged in
+	device := auth.Group("/device")
+	{
+		device.POST("/code", h.device.Authorize)
+		device.POST("/token", h.device.Token)
+		device.POST("/approve", authMiddleware, h.device.Decide)
+	}
+
+	// Session routes (protected)
+	sessions := auth.Group("/sessions")
+	sessions.Use(authMiddleware)
+	{
+		sessions.GET("", h.session.List)
+		sessions.DELETE("/:id", h.session.Revoke)
+		sessions.POST("/revoke-others", h.session.RevokeOthers)
+	}
+
+	// Personal access token routes (protected)
+	tokens := auth.Group("/tokens")
+	tokens.Use(authMiddleware)
+	{
+		tokens.POST("", h.token.Create)
+		tokens.GET("", h.token.List)
+		tokens.DELETE("/:id", h.token.Revoke)
 	}
 
 	// Todo routes (protected)
 	todos := api.Group("/todos")
-	todos.Use(middleware.AuthMiddleware(authService))
+	todos.Use(tokenAuthMiddleware)
+	if cfg.Auth.RequireEmailVerification {
+		todos.Use(middleware.RequireVerifiedEmail())
+	}
 	{
-		todos.POST("", todoHandler.Create)
-		todos.GET("", todoHandler.GetList)
-		todos.GET("/:id", todoHandler.GetByID)
-		todos.PUT("/:id", todoHandler.Update)
-		todos.DELETE("/:id", todoHandler.Delete)
-		todos.PATCH("/:id/toggle", todoHandler.ToggleStatus)
+		todos.POST("", middleware.RequireScope(model.ScopeTodosWrite), h.todo.Create)
+		todos.GET("", middleware.RequireScope(model.ScopeTodosRead), h.todo.GetList)
+		todos.GET("/:id", middleware.RequireScope(model.ScopeTodosRead), h.todo.GetByID)
+		todos.PUT("/:id", middleware.RequireScope(model.ScopeTodosWrite), h.todo.Update)
+		todos.DELETE("/:id", middleware.RequireScope(model.ScopeTodosWrite), h.todo.Delete)
+		todos.PATCH("/:id/toggle", middleware.RequireScope(model.ScopeTodosWrite), h.todo.ToggleStatus)
+		todos.DELETE("/:id/recurrence", middleware.RequireScope(model.ScopeTodosWrite), h.todo.StopRecurrence)
+		todos.GET("/:id/items", middleware.RequireScope(model.ScopeTodosRead), h.todoItem.List)
+		todos.POST("/:id/items", middleware.RequireScope(model.ScopeTodosWrite), h.todoItem.Create)
+		todos.POST("/:id/items/reorder", middleware.RequireScope(model.ScopeTodosWrite), h.todoItem.Reorder)
+		todos.PUT("/:id/items/:itemId", middleware.RequireScope(model.ScopeTodosWrite), h.todoItem.Update)
+		todos.DELETE("/:id/items/:itemId", middleware.RequireScope(model.ScopeTodosWrite), h.todoItem.Delete)
+		todos.GET("/:id/reminders", middleware.RequireScope(model.ScopeTodosRead), h.reminder.List)
+		todos.POST("/:id/reminders", middleware.RequireScope(model.ScopeTodosWrite), h.reminder.Create)
+		todos.DELETE("/:id/reminders/:reminderId", middleware.RequireScope(model.ScopeTodosWrite), h.reminder.Delete)
+	}
+
+	// Tag routes (protected)
+	tags := api.Group("/tags")
+	tags.Use(tokenAuthMiddleware)
+	if cfg.Auth.RequireEmailVerification {
+		tags.Use(middleware.RequireVerifiedEmail())
+	}
+	{
+		tags.GET("", middleware.RequireScope(model.ScopeTodosRead), h.tag.List)
+		tags.POST("", middleware.RequireScope(model.ScopeTodosWrite), h.tag.Create)
+		tags.PUT("/:id", middleware.RequireScope(model.ScopeTodosWrite), h.tag.Update)
+		tags.DELETE("/:id", middleware.RequireScope(model.ScopeTodosWrite), h.tag.Delete)
+	}
+
+	// Project routes (protected)
+	projects := api.Group("/projects")
+	projects.Use(tokenAuthMiddleware)
+	if cfg.Auth.RequireEmailVerification {
+		projects.Use(middleware.RequireVerifiedEmail())
+	}
+	{
+		projects.GET("", middleware.RequireScope(model.ScopeTodosRead), h.project.List)
+		projects.POST("", middleware.RequireScope(model.ScopeTodosWrite), h.project.Create)
+		projects.GET("/:id", middleware.RequireScope(model.ScopeTodosRead), h.project.GetByID)
+		projects.PUT("/:id", middleware.RequireScope(model.ScopeTodosWrite), h.project.Update)
+		projects.DELETE("/:id", middleware.RequireScope(model.ScopeTodosWrite), h.project.Delete)
+	}
+
+	// Admin routes (protected, admin role only)
+	admin := api.Group("/admin")
+	admin.Use(authMiddleware, middleware.RequireRole(model.RoleAdmin))
+	{
+		admin.GET("/users", h.admin.ListUsers)
+		admin.GET("/users/:id", h.admin.GetUser)
+		admin.POST("/users/:id/disable", h.admin.DisableUser)
+		admin.POST("/users/:id/enable", h.admin.EnableUser)
+		admin.GET("/users/:id/todos", h.admin.GetUserTodos)
+		admin.GET("/audit-logs", h.admin.ListAuditLogs)
+	}
+
+	// SCIM provisioning routes (protected by the SCIM bearer secret)
+	if h.scim != nil {
+		scim := router.Group("/scim/v2")
+		scim.Use(middleware.SCIMAuthMiddleware(cfg.SCIM.Token))
+		{
+			scim.POST("/Users", h.scim.CreateUser)
+			scim.GET("/Users", h.scim.ListUsers)
+			scim.GET("/Users/:id", h.scim.GetUser)
+			scim.PATCH("/Users/:id", h.scim.PatchUser)
+			scim.DELETE("/Users/:id", h.scim.DeleteUser)
+		}
 	}
 
 	return router
@@ -3,23 +3,33 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration values
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	CORS     CORSConfig     `mapstructure:"cors"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	JWT       JWTConfig       `mapstructure:"jwt"`
+	CORS      CORSConfig      `mapstructure:"cors"`
+	Auth      AuthConfig      `mapstructure:"auth"`
+	Mail      MailConfig      `mapstructure:"mail"`
+	OIDC      OIDCConfig      `mapstructure:"oidc"`
+	WebAuthn  WebAuthnConfig  `mapstructure:"webauthn"`
+	ProxyAuth ProxyAuthConfig `mapstructure:"proxy_auth"`
+	SCIM      SCIMConfig      `mapstructure:"scim"`
+	Reminders RemindersConfig `mapstructure:"reminders"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string `mapstructure:"port"`
-	Host string `mapstructure:"host"`
-	Mode string `mapstructure:"mode"`
+	Port           string   `mapstructure:"port"`
+	Host           string   `mapstructure:"host"`
+	Mode           string   `mapstructure:"mode"`
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // DatabaseConfig holds database configuration
@@ -34,9 +44,14 @@ type DatabaseConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret          string `mapstructure:"secret"`
-	ExpirationHours int    `mapstructure:"expiration_hours"`
-	RefreshHours    int    `mapstructure:"refresh_hours"`
+	Secret           string `mapstructure:"secret"`
+	ExpirationHours  int    `mapstructure:"expiration_hours"`
+	RefreshHours     int    `mapstructure:"refresh_hours"`
+	RevocationStore  string `mapstructure:"revocation_store"`
+	Algorithm        string `mapstructure:"algorithm"`
+	AcceptHS256      bool   `mapstructure:"accept_hs256"`
+	KeyRotationHours int    `mapstructure:"key_rotation_hours"`
+	KeyGraceHours    int    `mapstructure:"key_grace_hours"`
 }
 
 // CORSConfig holds CORS configuration
@@ -47,6 +62,111 @@ type CORSConfig struct {
 	AllowCredentials bool     `mapstructure:"allow_credentials"`
 }
 
+// AuthConfig holds account related configuration
+type AuthConfig struct {
+	PasswordResetMinutes       int      `mapstructure:"password_reset_minutes"`
+	MagicLinkMinutes           int      `mapstructure:"magic_link_minutes"`
+	MagicLinkRequestsPerHour   int      `mapstructure:"magic_link_requests_per_hour"`
+	DeviceCodeMinutes          int      `mapstructure:"device_code_minutes"`
+	DevicePollIntervalSeconds  int      `mapstructure:"device_poll_interval_seconds"`
+	EmailVerificationHours     int      `mapstructure:"email_verification_hours"`
+	RequireEmailVerification   bool     `mapstructure:"require_email_verification"`
+	EncryptionKey              string   `mapstructure:"encryption_key"`
+	MFAIssuer                  string   `mapstructure:"mfa_issuer"`
+	MFAChallengeMinutes        int      `mapstructure:"mfa_challenge_minutes"`
+	AttemptStore               string   `mapstructure:"attempt_store"`
+	MaxLoginAttempts           int      `mapstructure:"max_login_attempts"`
+	MaxLoginAttemptsPerIP      int      `mapstructure:"max_login_attempts_per_ip"`
+	LoginAttemptWindowMinutes  int      `mapstructure:"login_attempt_window_minutes"`
+	LoginDelayAfter            int      `mapstructure:"login_delay_after"`
+	LoginMaxDelaySeconds       int      `mapstructure:"login_max_delay_seconds"`
+	LockoutMinutes             int      `mapstructure:"lockout_minutes"`
+	AccountPurgeDays           int      `mapstructure:"account_purge_days"`
+	ReauthMinutes              int      `mapstructure:"reauth_minutes"`
+	AdminEmails                []string `mapstructure:"admin_emails"`
+	PasswordHashAlgorithm      string   `mapstructure:"password_hash_algorithm"`
+	Argon2MemoryKiB            int      `mapstructure:"argon2_memory_kib"`
+	Argon2Iterations           int      `mapstructure:"argon2_iterations"`
+	Argon2Parallelism          int      `mapstructure:"argon2_parallelism"`
+	BcryptCost                 int      `mapstructure:"bcrypt_cost"`
+	PasswordMinLength          int      `mapstructure:"password_min_length"`
+	PasswordMaxLength          int      `mapstructure:"password_max_length"`
+	PasswordMinScore           int      `mapstructure:"password_min_score"`
+	PasswordRejectPersonalInfo bool     `mapstructure:"password_reject_personal_info"`
+	BreachedPasswordsPath      string   `mapstructure:"breached_passwords_path"`
+	CookieAuth                 bool     `mapstructure:"cookie_auth"`
+	CookieDomain               string   `mapstructure:"cookie_domain"`
+	CookieSecure               bool     `mapstructure:"cookie_secure"`
+	CookieSameSite             string   `mapstructure:"cookie_same_site"`
+}
+
+// MailConfig holds outgoing email configuration
+type MailConfig struct {
+	Driver       string `mapstructure:"driver"`
+	From         string `mapstructure:"from"`
+	OutboxDir    string `mapstructure:"outbox_dir"`
+	LinkBaseURL  string `mapstructure:"link_base_url"`
+	SMTPHost     string `mapstructure:"smtp_host"`
+	SMTPPort     int    `mapstructure:"smtp_port"`
+	SMTPUsername string `mapstructure:"smtp_username"`
+	SMTPPassword string `mapstructure:"smtp_password"`
+}
+
+// OIDCConfig holds OpenID Connect single sign-on configuration
+type OIDCConfig struct {
+	Enabled           bool     `mapstructure:"enabled"`
+	Issuer            string   `mapstructure:"issuer"`
+	ClientID          string   `mapstructure:"client_id"`
+	ClientSecret      string   `mapstructure:"client_secret"`
+	RedirectURL       string   `mapstructure:"redirect_url"`
+	Scopes            []string `mapstructure:"scopes"`
+	AllowSignup       bool     `mapstructure:"allow_signup"`
+	LoginStateMinutes int      `mapstructure:"login_state_minutes"`
+}
+
+// WebAuthnConfig holds passkey relying party configuration
+type WebAuthnConfig struct {
+	RPID             string   `mapstructure:"rp_id"`
+	RPName           string   `mapstructure:"rp_name"`
+	Origins          []string `mapstructure:"origins"`
+	ChallengeMinutes int      `mapstructure:"challenge_minutes"`
+}
+
+// ProxyAuthConfig holds configuration for trusting an authenticating reverse proxy
+type ProxyAuthConfig struct {
+	Enabled       bool     `mapstructure:"enabled"`
+	TrustedCIDRs  []string `mapstructure:"trusted_cidrs"`
+	UserHeader    string   `mapstructure:"user_header"`
+	EmailHeader   string   `mapstructure:"email_header"`
+	EmailDomain   string   `mapstructure:"email_domain"`
+	AutoProvision bool     `mapstructure:"auto_provision"`
+}
+
+// SCIMConfig holds SCIM 2.0 user provisioning configuration
+type SCIMConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Token   string `mapstructure:"token"`
+}
+
+// RemindersConfig holds due date reminder scheduling and delivery configuration
+type RemindersConfig struct {
+	Enabled               bool     `mapstructure:"enabled"`
+	Channels              []string `mapstructure:"channels"`
+	PollSeconds           int      `mapstructure:"poll_seconds"`
+	BatchSize             int      `mapstructure:"batch_size"`
+	ClaimSeconds          int      `mapstructure:"claim_seconds"`
+	MaxAttempts           int      `mapstructure:"max_attempts"`
+	WebhookURL            string   `mapstructure:"webhook_url"`
+	WebhookSecret         string   `mapstructure:"webhook_secret"`
+	WebhookTimeoutSeconds int      `mapstructure:"webhook_timeout_seconds"`
+}
+
+// RateLimitConfig holds rate limits of endpoints that accept guessable codes
+type RateLimitConfig struct {
+	DeviceApprovalAttempts      int `mapstructure:"device_approval_attempts"`
+	DeviceApprovalWindowMinutes int `mapstructure:"device_approval_window_minutes"`
+}
+
 // LoadConfig loads configuration from environment variables and config file
 func LoadConfig() (*Config, error) {
 	config := &Config{}
@@ -55,6 +175,7 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.mode", "debug")
+	viper.SetDefault("server.trusted_proxies", []string{})
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", 5432)
 	viper.SetDefault("database.username", "postgres")
@@ -64,10 +185,81 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("jwt.secret", "your-secret-key")
 	viper.SetDefault("jwt.expiration_hours", 24)
 	viper.SetDefault("jwt.refresh_hours", 168)
+	viper.SetDefault("jwt.revocation_store", "memory")
+	viper.SetDefault("jwt.algorithm", "HS256")
+	viper.SetDefault("jwt.accept_hs256", false)
+	viper.SetDefault("jwt.key_rotation_hours", 720)
+	viper.SetDefault("jwt.key_grace_hours", 48)
 	viper.SetDefault("cors.allow_origins", []string{"*"})
-	viper.SetDefault("cors.allow_methods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
-	viper.SetDefault("cors.allow_headers", []string{"Origin", "Content-Type", "Accept", "Authorization"})
+	viper.SetDefault("cors.allow_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	viper.SetDefault("cors.allow_headers", []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token"})
 	viper.SetDefault("cors.allow_credentials", true)
+	viper.SetDefault("auth.password_reset_minutes", 30)
+	viper.SetDefault("auth.magic_link_minutes", 15)
+	viper.SetDefault("auth.magic_link_requests_per_hour", 5)
+	viper.SetDefault("auth.device_code_minutes", 10)
+	viper.SetDefault("auth.device_poll_interval_seconds", 5)
+	viper.SetDefault("auth.email_verification_hours", 48)
+	viper.SetDefault("auth.require_email_verification", false)
+	viper.SetDefault("auth.encryption_key", "your-encryption-key")
+	viper.SetDefault("auth.mfa_issuer", "Todo App")
+	viper.SetDefault("auth.mfa_challenge_minutes", 5)
+	viper.SetDefault("auth.attempt_store", "memory")
+	viper.SetDefault("auth.max_login_attempts", 5)
+	viper.SetDefault("auth.max_login_attempts_per_ip", 50)
+	viper.SetDefault("auth.login_attempt_window_minutes", 15)
+	viper.SetDefault("auth.login_delay_after", 3)
+	viper.SetDefault("auth.login_max_delay_seconds", 30)
+	viper.SetDefault("auth.lockout_minutes", 15)
+	viper.SetDefault("auth.account_purge_days", 30)
+	viper.SetDefault("auth.reauth_minutes", 10)
+	viper.SetDefault("auth.admin_emails", []string{})
+	viper.SetDefault("auth.password_hash_algorithm", "argon2id")
+	viper.SetDefault("auth.argon2_memory_kib", 65536)
+	viper.SetDefault("auth.argon2_iterations", 3)
+	viper.SetDefault("auth.argon2_parallelism", 2)
+	viper.SetDefault("auth.bcrypt_cost", 12)
+	viper.SetDefault("auth.password_min_length", 8)
+	viper.SetDefault("auth.password_max_length", 128)
+	viper.SetDefault("auth.password_min_score", 2)
+	viper.SetDefault("auth.password_reject_personal_info", true)
+	viper.SetDefault("auth.breached_passwords_path", "")
+	viper.SetDefault("auth.cookie_auth", false)
+	viper.SetDefault("auth.cookie_domain", "")
+	viper.SetDefault("auth.cookie_secure", true)
+	viper.SetDefault("auth.cookie_same_site", "lax")
+	viper.SetDefault("mail.driver", "stdout")
+	viper.SetDefault("mail.from", "Todo App <no-reply@localhost>")
+	viper.SetDefault("mail.outbox_dir", "./tmp/outbox")
+	viper.SetDefault("mail.link_base_url", "http://localhost:3000")
+	viper.SetDefault("mail.smtp_host", "localhost")
+	viper.SetDefault("mail.smtp_port", 25)
+	viper.SetDefault("oidc.enabled", false)
+	viper.SetDefault("oidc.redirect_url", "http://localhost:3000/auth/callback")
+	viper.SetDefault("oidc.scopes", []string{"openid", "email", "profile"})
+	viper.SetDefault("oidc.allow_signup", true)
+	viper.SetDefault("oidc.login_state_minutes", 10)
+	viper.SetDefault("webauthn.rp_id", "localhost")
+	viper.SetDefault("webauthn.rp_name", "Todo App")
+	viper.SetDefault("webauthn.origins", []string{"http://localhost:3000"})
+	viper.SetDefault("webauthn.challenge_minutes", 5)
+	viper.SetDefault("proxy_auth.enabled", false)
+	viper.SetDefault("proxy_auth.trusted_cidrs", []string{})
+	viper.SetDefault("proxy_auth.user_header", "X-Forwarded-User")
+	viper.SetDefault("proxy_auth.email_header", "X-Forwarded-Email")
+	viper.SetDefault("proxy_auth.email_domain", "")
+	viper.SetDefault("proxy_auth.auto_provision", true)
+	viper.SetDefault("scim.enabled", false)
+	viper.SetDefault("reminders.enabled", true)
+	viper.SetDefault("reminders.channels", []string{"log"})
+	viper.SetDefault("reminders.poll_seconds", 30)
+	viper.SetDefault("reminders.batch_size", 50)
+	viper.SetDefault("reminders.claim_seconds", 600)
+	viper.SetDefault("reminders.max_attempts", 5)
+	viper.SetDefault("reminders.webhook_url", "")
+	viper.SetDefault("reminders.webhook_timeout_seconds", 10)
+	viper.SetDefault("rate_limit.device_approval_attempts", 10)
+	viper.SetDefault("rate_limit.device_approval_window_minutes", 15)
 
 	// Read from environment variables
 	viper.AutomaticEnv()
@@ -82,6 +274,9 @@ func LoadConfig() (*Config, error) {
 	if mode := os.Getenv("GIN_MODE"); mode != "" {
 		viper.Set("server.mode", mode)
 	}
+	if trustedProxies := os.Getenv("TRUSTED_PROXIES"); trustedProxies != "" {
+		viper.Set("server.trusted_proxies", strings.Split(trustedProxies, ","))
+	}
 	if dbHost := os.Getenv("DB_HOST"); dbHost != "" {
 		viper.Set("database.host", dbHost)
 	}
@@ -110,6 +305,335 @@ func LoadConfig() (*Config, error) {
 			viper.Set("jwt.expiration_hours", hours)
 		}
 	}
+	if jwtRefresh := os.Getenv("JWT_REFRESH_HOURS"); jwtRefresh != "" {
+		if hours, err := strconv.Atoi(jwtRefresh); err == nil {
+			viper.Set("jwt.refresh_hours", hours)
+		}
+	}
+	if revocationStore := os.Getenv("JWT_REVOCATION_STORE"); revocationStore != "" {
+		viper.Set("jwt.revocation_store", revocationStore)
+	}
+	if jwtAlgorithm := os.Getenv("JWT_ALGORITHM"); jwtAlgorithm != "" {
+		viper.Set("jwt.algorithm", jwtAlgorithm)
+	}
+	if acceptHS256 := os.Getenv("JWT_ACCEPT_HS256"); acceptHS256 != "" {
+		if accept, err := strconv.ParseBool(acceptHS256); err == nil {
+			viper.Set("jwt.accept_hs256", accept)
+		}
+	}
+	if rotationHours := os.Getenv("JWT_KEY_ROTATION_HOURS"); rotationHours != "" {
+		if hours, err := strconv.Atoi(rotationHours); err == nil {
+			viper.Set("jwt.key_rotation_hours", hours)
+		}
+	}
+	if graceHours := os.Getenv("JWT_KEY_GRACE_HOURS"); graceHours != "" {
+		if hours, err := strconv.Atoi(graceHours); err == nil {
+			viper.Set("jwt.key_grace_hours", hours)
+		}
+	}
+	if resetMinutes := os.Getenv("PASSWORD_RESET_MINUTES"); resetMinutes != "" {
+		if minutes, err := strconv.Atoi(resetMinutes); err == nil {
+			viper.Set("auth.password_reset_minutes", minutes)
+		}
+	}
+	if magicLinkMinutes := os.Getenv("MAGIC_LINK_MINUTES"); magicLinkMinutes != "" {
+		if minutes, err := strconv.Atoi(magicLinkMinutes); err == nil {
+			viper.Set("auth.magic_link_minutes", minutes)
+		}
+	}
+	if magicLinkRequests := os.Getenv("MAGIC_LINK_REQUESTS_PER_HOUR"); magicLinkRequests != "" {
+		if requests, err := strconv.Atoi(magicLinkRequests); err == nil {
+			viper.Set("auth.magic_link_requests_per_hour", requests)
+		}
+	}
+	if deviceCodeMinutes := os.Getenv("DEVICE_CODE_MINUTES"); deviceCodeMinutes != "" {
+		if minutes, err := strconv.Atoi(deviceCodeMinutes); err == nil {
+			viper.Set("auth.device_code_minutes", minutes)
+		}
+	}
+	if pollInterval := os.Getenv("DEVICE_POLL_INTERVAL_SECONDS"); pollInterval != "" {
+		if seconds, err := strconv.Atoi(pollInterval); err == nil {
+			viper.Set("auth.device_poll_interval_seconds", seconds)
+		}
+	}
+	if verificationHours := os.Getenv("EMAIL_VERIFICATION_HOURS"); verificationHours != "" {
+		if hours, err := strconv.Atoi(verificationHours); err == nil {
+			viper.Set("auth.email_verification_hours", hours)
+		}
+	}
+	if requireVerification := os.Getenv("REQUIRE_EMAIL_VERIFICATION"); requireVerification != "" {
+		if require, err := strconv.ParseBool(requireVerification); err == nil {
+			viper.Set("auth.require_email_verification", require)
+		}
+	}
+	if encryptionKey := os.Getenv("ENCRYPTION_KEY"); encryptionKey != "" {
+		viper.Set("auth.encryption_key", encryptionKey)
+	}
+	if mfaIssuer := os.Getenv("MFA_ISSUER"); mfaIssuer != "" {
+		viper.Set("auth.mfa_issuer", mfaIssuer)
+	}
+	if challengeMinutes := os.Getenv("MFA_CHALLENGE_MINUTES"); challengeMinutes != "" {
+		if minutes, err := strconv.Atoi(challengeMinutes); err == nil {
+			viper.Set("auth.mfa_challenge_minutes", minutes)
+		}
+	}
+	if attemptStore := os.Getenv("LOGIN_ATTEMPT_STORE"); attemptStore != "" {
+		viper.Set("auth.attempt_store", attemptStore)
+	}
+	if maxAttempts := os.Getenv("MAX_LOGIN_ATTEMPTS"); maxAttempts != "" {
+		if attempts, err := strconv.Atoi(maxAttempts); err == nil {
+			viper.Set("auth.max_login_attempts", attempts)
+		}
+	}
+	if maxAttemptsPerIP := os.Getenv("MAX_LOGIN_ATTEMPTS_PER_IP"); maxAttemptsPerIP != "" {
+		if attempts, err := strconv.Atoi(maxAttemptsPerIP); err == nil {
+			viper.Set("auth.max_login_attempts_per_ip", attempts)
+		}
+	}
+	if lockoutMinutes := os.Getenv("LOCKOUT_MINUTES"); lockoutMinutes != "" {
+		if minutes, err := strconv.Atoi(lockoutMinutes); err == nil {
+			viper.Set("auth.lockout_minutes", minutes)
+		}
+	}
+	if attemptWindow := os.Getenv("LOGIN_ATTEMPT_WINDOW_MINUTES"); attemptWindow != "" {
+		if minutes, err := strconv.Atoi(attemptWindow); err == nil {
+			viper.Set("auth.login_attempt_window_minutes", minutes)
+		}
+	}
+	if delayAfter := os.Getenv("LOGIN_DELAY_AFTER"); delayAfter != "" {
+		if attempts, err := strconv.Atoi(delayAfter); err == nil {
+			viper.Set("auth.login_delay_after", attempts)
+		}
+	}
+	if maxDelay := os.Getenv("LOGIN_MAX_DELAY_SECONDS"); maxDelay != "" {
+		if seconds, err := strconv.Atoi(maxDelay); err == nil {
+			viper.Set("auth.login_max_delay_seconds", seconds)
+		}
+	}
+	if purgeDays := os.Getenv("ACCOUNT_PURGE_DAYS"); purgeDays != "" {
+		if days, err := strconv.Atoi(purgeDays); err == nil {
+			viper.Set("auth.account_purge_days", days)
+		}
+	}
+	if reauthMinutes := os.Getenv("REAUTH_MINUTES"); reauthMinutes != "" {
+		if minutes, err := strconv.Atoi(reauthMinutes); err == nil {
+			viper.Set("auth.reauth_minutes", minutes)
+		}
+	}
+	if adminEmails := os.Getenv("ADMIN_EMAILS"); adminEmails != "" {
+		viper.Set("auth.admin_emails", strings.Split(adminEmails, ","))
+	}
+	if hashAlgorithm := os.Getenv("PASSWORD_HASH_ALGORITHM"); hashAlgorithm != "" {
+		viper.Set("auth.password_hash_algorithm", hashAlgorithm)
+	}
+	if argon2Memory := os.Getenv("ARGON2_MEMORY_KIB"); argon2Memory != "" {
+		if memory, err := strconv.Atoi(argon2Memory); err == nil {
+			viper.Set("auth.argon2_memory_kib", memory)
+		}
+	}
+	if argon2Iterations := os.Getenv("ARGON2_ITERATIONS"); argon2Iterations != "" {
+		if iterations, err := strconv.Atoi(argon2Iterations); err == nil {
+			viper.Set("auth.argon2_iterations", iterations)
+		}
+	}
+	if argon2Parallelism := os.Getenv("ARGON2_PARALLELISM"); argon2Parallelism != "" {
+		if parallelism, err := strconv.Atoi(argon2Parallelism); err == nil {
+			viper.Set("auth.argon2_parallelism", parallelism)
+		}
+	}
+	if bcryptCost := os.Getenv("BCRYPT_COST"); bcryptCost != "" {
+		if cost, err := strconv.Atoi(bcryptCost); err == nil {
+			viper.Set("auth.bcrypt_cost", cost)
+		}
+	}
+	if minLength := os.Getenv("PASSWORD_MIN_LENGTH"); minLength != "" {
+		if length, err := strconv.Atoi(minLength); err == nil {
+			viper.Set("auth.password_min_length", length)
+		}
+	}
+	if maxLength := os.Getenv("PASSWORD_MAX_LENGTH"); maxLength != "" {
+		if length, err := strconv.Atoi(maxLength); err == nil {
+			viper.Set("auth.password_max_length", length)
+		}
+	}
+	if minScore := os.Getenv("PASSWORD_MIN_SCORE"); minScore != "" {
+		if score, err := strconv.Atoi(minScore); err == nil {
+			viper.Set("auth.password_min_score", score)
+		}
+	}
+	if rejectPersonalInfo := os.Getenv("PASSWORD_REJECT_PERSONAL_INFO"); rejectPersonalInfo != "" {
+		if reject, err := strconv.ParseBool(rejectPersonalInfo); err == nil {
+			viper.Set("auth.password_reject_personal_info", reject)
+		}
+	}
+	if breachedPath := os.Getenv("BREACHED_PASSWORDS_PATH"); breachedPath != "" {
+		viper.Set("auth.breached_passwords_path", breachedPath)
+	}
+	if cookieAuth := os.Getenv("COOKIE_AUTH"); cookieAuth != "" {
+		if enabled, err := strconv.ParseBool(cookieAuth); err == nil {
+			viper.Set("auth.cookie_auth", enabled)
+		}
+	}
+	if cookieDomain := os.Getenv("COOKIE_DOMAIN"); cookieDomain != "" {
+		viper.Set("auth.cookie_domain", cookieDomain)
+	}
+	if cookieSecure := os.Getenv("COOKIE_SECURE"); cookieSecure != "" {
+		if secure, err := strconv.ParseBool(cookieSecure); err == nil {
+			viper.Set("auth.cookie_secure", secure)
+		}
+	}
+	if cookieSameSite := os.Getenv("COOKIE_SAME_SITE"); cookieSameSite != "" {
+		viper.Set("auth.cookie_same_site", cookieSameSite)
+	}
+	if mailDriver := os.Getenv("MAIL_DRIVER"); mailDriver != "" {
+		viper.Set("mail.driver", mailDriver)
+	}
+	if mailFrom := os.Getenv("MAIL_FROM"); mailFrom != "" {
+		viper.Set("mail.from", mailFrom)
+	}
+	if outboxDir := os.Getenv("MAIL_OUTBOX_DIR"); outboxDir != "" {
+		viper.Set("mail.outbox_dir", outboxDir)
+	}
+	if linkBaseURL := os.Getenv("MAIL_LINK_BASE_URL"); linkBaseURL != "" {
+		viper.Set("mail.link_base_url", linkBaseURL)
+	}
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		viper.Set("mail.smtp_host", smtpHost)
+	}
+	if smtpPort := os.Getenv("SMTP_PORT"); smtpPort != "" {
+		if port, err := strconv.Atoi(smtpPort); err == nil {
+			viper.Set("mail.smtp_port", port)
+		}
+	}
+	if smtpUser := os.Getenv("SMTP_USERNAME"); smtpUser != "" {
+		viper.Set("mail.smtp_username", smtpUser)
+	}
+	if smtpPassword := os.Getenv("SMTP_PASSWORD"); smtpPassword != "" {
+		viper.Set("mail.smtp_password", smtpPassword)
+	}
+	if oidcEnabled := os.Getenv("OIDC_ENABLED"); oidcEnabled != "" {
+		if enabled, err := strconv.ParseBool(oidcEnabled); err == nil {
+			viper.Set("oidc.enabled", enabled)
+		}
+	}
+	if oidcIssuer := os.Getenv("OIDC_ISSUER"); oidcIssuer != "" {
+		viper.Set("oidc.issuer", oidcIssuer)
+	}
+	if oidcClientID := os.Getenv("OIDC_CLIENT_ID"); oidcClientID != "" {
+		viper.Set("oidc.client_id", oidcClientID)
+	}
+	if oidcClientSecret := os.Getenv("OIDC_CLIENT_SECRET"); oidcClientSecret != "" {
+		viper.Set("oidc.client_secret", oidcClientSecret)
+	}
+	if oidcRedirectURL := os.Getenv("OIDC_REDIRECT_URL"); oidcRedirectURL != "" {
+		viper.Set("oidc.redirect_url", oidcRedirectURL)
+	}
+	if oidcScopes := os.Getenv("OIDC_SCOPES"); oidcScopes != "" {
+		viper.Set("oidc.scopes", strings.Split(oidcScopes, ","))
+	}
+	if allowSignup := os.Getenv("OIDC_ALLOW_SIGNUP"); allowSignup != "" {
+		if allow, err := strconv.ParseBool(allowSignup); err == nil {
+			viper.Set("oidc.allow_signup", allow)
+		}
+	}
+	if stateMinutes := os.Getenv("OIDC_LOGIN_STATE_MINUTES"); stateMinutes != "" {
+		if minutes, err := strconv.Atoi(stateMinutes); err == nil {
+			viper.Set("oidc.login_state_minutes", minutes)
+		}
+	}
+	if rpID := os.Getenv("WEBAUTHN_RP_ID"); rpID != "" {
+		viper.Set("webauthn.rp_id", rpID)
+	}
+	if rpName := os.Getenv("WEBAUTHN_RP_NAME"); rpName != "" {
+		viper.Set("webauthn.rp_name", rpName)
+	}
+	if origins := os.Getenv("WEBAUTHN_ORIGINS"); origins != "" {
+		viper.Set("webauthn.origins", strings.Split(origins, ","))
+	}
+	if challengeMinutes := os.Getenv("WEBAUTHN_CHALLENGE_MINUTES"); challengeMinutes != "" {
+		if minutes, err := strconv.Atoi(challengeMinutes); err == nil {
+			viper.Set("webauthn.challenge_minutes", minutes)
+		}
+	}
+	if proxyAuth := os.Getenv("PROXY_AUTH_ENABLED"); proxyAuth != "" {
+		if enabled, err := strconv.ParseBool(proxyAuth); err == nil {
+			viper.Set("proxy_auth.enabled", enabled)
+		}
+	}
+	if trustedCIDRs := os.Getenv("PROXY_AUTH_TRUSTED_CIDRS"); trustedCIDRs != "" {
+		viper.Set("proxy_auth.trusted_cidrs", strings.Split(trustedCIDRs, ","))
+	}
+	if userHeader := os.Getenv("PROXY_AUTH_USER_HEADER"); userHeader != "" {
+		viper.Set("proxy_auth.user_header", userHeader)
+	}
+	if emailHeader := os.Getenv("PROXY_AUTH_EMAIL_HEADER"); emailHeader != "" {
+		viper.Set("proxy_auth.email_header", emailHeader)
+	}
+	if emailDomain := os.Getenv("PROXY_AUTH_EMAIL_DOMAIN"); emailDomain != "" {
+		viper.Set("proxy_auth.email_domain", emailDomain)
+	}
+	if autoProvision := os.Getenv("PROXY_AUTH_AUTO_PROVISION"); autoProvision != "" {
+		if provision, err := strconv.ParseBool(autoProvision); err == nil {
+			viper.Set("proxy_auth.auto_provision", provision)
+		}
+	}
+	if scimEnabled := os.Getenv("SCIM_ENABLED"); scimEnabled != "" {
+		if enabled, err := strconv.ParseBool(scimEnabled); err == nil {
+			viper.Set("scim.enabled", enabled)
+		}
+	}
+	if scimToken := os.Getenv("SCIM_TOKEN"); scimToken != "" {
+		viper.Set("scim.token", scimToken)
+	}
+	if remindersEnabled := os.Getenv("REMINDERS_ENABLED"); remindersEnabled != "" {
+		if enabled, err := strconv.ParseBool(remindersEnabled); err == nil {
+			viper.Set("reminders.enabled", enabled)
+		}
+	}
+	if channels := os.Getenv("REMINDER_CHANNELS"); channels != "" {
+		viper.Set("reminders.channels", strings.Split(channels, ","))
+	}
+	if pollSeconds := os.Getenv("REMINDER_POLL_SECONDS"); pollSeconds != "" {
+		if seconds, err := strconv.Atoi(pollSeconds); err == nil {
+			viper.Set("reminders.poll_seconds", seconds)
+		}
+	}
+	if batchSize := os.Getenv("REMINDER_BATCH_SIZE"); batchSize != "" {
+		if size, err := strconv.Atoi(batchSize); err == nil {
+			viper.Set("reminders.batch_size", size)
+		}
+	}
+	if claimSeconds := os.Getenv("REMINDER_CLAIM_SECONDS"); claimSeconds != "" {
+		if seconds, err := strconv.Atoi(claimSeconds); err == nil {
+			viper.Set("reminders.claim_seconds", seconds)
+		}
+	}
+	if maxAttempts := os.Getenv("REMINDER_MAX_ATTEMPTS"); maxAttempts != "" {
+		if attempts, err := strconv.Atoi(maxAttempts); err == nil {
+			viper.Set("reminders.max_attempts", attempts)
+		}
+	}
+	if webhookURL := os.Getenv("REMINDER_WEBHOOK_URL"); webhookURL != "" {
+		viper.Set("reminders.webhook_url", webhookURL)
+	}
+	if webhookSecret := os.Getenv("REMINDER_WEBHOOK_SECRET"); webhookSecret != "" {
+		viper.Set("reminders.webhook_secret", webhookSecret)
+	}
+	if webhookTimeout := os.Getenv("REMINDER_WEBHOOK_TIMEOUT_SECONDS"); webhookTimeout != "" {
+		if seconds, err := strconv.Atoi(webhookTimeout); err == nil {
+			viper.Set("reminders.webhook_timeout_seconds", seconds)
+		}
+	}
+	if approvalAttempts := os.Getenv("DEVICE_APPROVAL_ATTEMPTS"); approvalAttempts != "" {
+		if attempts, err := strconv.Atoi(approvalAttempts); err == nil {
+			viper.Set("rate_limit.device_approval_attempts", attempts)
+		}
+	}
+	if approvalWindow := os.Getenv("DEVICE_APPROVAL_WINDOW_MINUTES"); approvalWindow != "" {
+		if minutes, err := strconv.Atoi(approvalWindow); err == nil {
+			viper.Set("rate_limit.device_approval_window_minutes", minutes)
+		}
+	}
 
 	// Unmarshal to struct
 	if err := viper.Unmarshal(config); err != nil {
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/middleware"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// AccountHandler handles self-service account requests
+type AccountHandler struct {
+	accountService service.AccountService
+	cookies        *middleware.TokenCookies
+	validator      *validator.Validate
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService service.AccountService, cookies *middleware.TokenCookies) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+		cookies:        cookies,
+		validator:      validator.New(),
+	}
+}
+
+// UpdateProfile handles profile updates
+// @Summary Update current user
+// @Description Edit the profile of the current user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param profile body model.UpdateProfileRequest true "Profile data"
+// @Success 200 {object} model.UserResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/me [patch]
+func (h *AccountHandler) UpdateProfile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	user, err := h.accountService.UpdateProfile(userID.(string), &req)
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToResponse())
+}
+
+// ChangePassword handles password changes
+// @Summary Change password
+// @Description Change the password of the current user. Every other session and token is revoked. Accounts without a password set their first one within a few minutes of signing in.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param password body model.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} model.LoginResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/me/password [post]
+func (h *AccountHandler) ChangePassword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, err := h.accountService.ChangePassword(userID.(string), c.GetString("session_id"), &req, clientInfo(c))
+	if err != nil {
+		if writeTooManyRequests(c, err) {
+			return
+		}
+		if writePasswordPolicyError(c, err) {
+			return
+		}
+		if err.Error() == "invalid password" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+			return
+		}
+		if err.Error() == "recent login required" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Sign in again to confirm this change"})
+			return
+		}
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	writeLoginResponse(c, h.cookies, response)
+}
+
+// DeleteAccount handles account deletion
+// @Summary Delete current user
+// @Description Delete the account of the current user and their todos. The data is purged after a grace period. Accounts without a password confirm within a few minutes of signing in instead.
+// @Tags auth
+// @Accept json
+// @Security BearerAuth
+// @Param confirmation body model.DeleteAccountRequest true "Password confirmation"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/me [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	if err := h.accountService.DeleteAccount(userID.(string), c.GetString("session_id"), &req, c.ClientIP()); err != nil {
+		if writeTooManyRequests(c, err) {
+			return
+		}
+		if err.Error() == "invalid password" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect"})
+			return
+		}
+		if err.Error() == "recent login required" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Sign in again to confirm this change"})
+			return
+		}
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	h.cookies.Clear(c)
+	c.Status(http.StatusNoContent)
+}
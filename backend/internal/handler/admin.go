@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// AdminHandler handles administrative requests
+type AdminHandler struct {
+	adminService service.AdminService
+	validator    *validator.Validate
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService service.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+		validator:    validator.New(),
+	}
+}
+
+// ListUsers handles user listing
+// @Summary List users
+// @Description Get users with pagination, search and filters
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param search query string false "Search in name and email"
+// @Param role query string false "Filter by role" Enums(user, admin)
+// @Param disabled query bool false "Filter by disabled state"
+// @Success 200 {object} model.UserListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	var req model.UserListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, err := h.adminService.ListUsers(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetUser handles user retrieval by ID
+// @Summary Get user by ID
+// @Description Get any user by their ID
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} model.UserResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/{id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, err := h.adminService.GetUser(c.Param("id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToResponse())
+}
+
+// DisableUser handles disabling a user
+// @Summary Disable user
+// @Description Block a user from logging in and revoke all of their tokens
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} model.UserResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/{id}/disable [post]
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.DisableUser(actor, c.Param("id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToResponse())
+}
+
+// EnableUser handles re-enabling a user
+// @Summary Enable user
+// @Description Let a disabled user log in again
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} model.UserResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/{id}/enable [post]
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.EnableUser(actor, c.Param("id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToResponse())
+}
+
+// GetUserTodos handles listing the todos of any user
+// @Summary List a user's todos
+// @Description Get the todos of any user with pagination and filters
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status" Enums(pending, completed)
+// @Param priority query string false "Filter by priority" Enums(low, medium, high)
+// @Param search query string false "Search in title and description"
+// @Success 200 {object} model.TodoListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/users/{id}/todos [get]
+func (h *AdminHandler) GetUserTodos(c *gin.Context) {
+	actor, ok := auditActor(c)
+	if !ok {
+		return
+	}
+
+	var req model.TodoListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters", "details": err.Error()})
+		return
+	}
+
+	response, err := h.adminService.GetUserTodos(actor, c.Param("id"), &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListAuditLogs handles audit log listing
+// @Summary List audit log
+// @Description Get recorded administrator actions, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(50)
+// @Param actor_id query string false "Filter by administrator"
+// @Param target_id query string false "Filter by target"
+// @Param action query string false "Filter by action"
+// @Success 200 {object} model.AuditLogListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/audit-logs [get]
+func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
+	var req model.AuditLogListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, err := h.adminService.ListAuditLogs(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// writeError maps admin service errors to responses
+func (h *AdminHandler) writeError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case "cannot disable own account":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot disable your own account"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
+
+// auditActor identifies the administrator making a request.
+// It writes a 401 response and returns false if there is none.
+func auditActor(c *gin.Context) (model.AuditActor, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return model.AuditActor{}, false
+	}
+
+	return model.AuditActor{UserID: userID.(string), IPAddress: c.ClientIP()}, true
+}
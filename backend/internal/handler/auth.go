@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/middleware"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
 )
@@ -12,13 +16,15 @@ import (
 // AuthHandler handles authentication related requests
 type AuthHandler struct {
 	authService service.AuthService
+	cookies     *middleware.TokenCookies
 	validator   *validator.Validate
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService service.AuthService) *AuthHandler {
+func NewAuthHandler(authService service.AuthService, cookies *middleware.TokenCookies) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
+		cookies:     cookies,
 		validator:   validator.New(),
 	}
 }
@@ -48,6 +54,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	user, err := h.authService.Register(&req)
 	if err != nil {
+		if writePasswordPolicyError(c, err) {
+			return
+		}
 		if err.Error() == "user with this email already exists" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
@@ -61,14 +70,16 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login handles user login
 // @Summary Login user
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return JWT token, or an MFA challenge if two-factor authentication is enabled
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param credentials body model.LoginRequest true "User login credentials"
 // @Success 200 {object} model.LoginResponse
+// @Success 202 {object} model.MFAChallenge
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -83,17 +94,373 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := h.authService.Login(&req)
+	response, challenge, err := h.authService.Login(&req, clientInfo(c))
 	if err != nil {
+		if writeTooManyRequests(c, err) {
+			return
+		}
 		if err.Error() == "invalid email or password" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
+		if err.Error() == "account disabled" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, challenge)
+		return
+	}
+
+	writeLoginResponse(c, h.cookies, response)
+}
+
+// Refresh handles access token refresh
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access and refresh token pair. In cookie auth mode the refresh token cookie is used when the body is empty.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body model.RefreshRequest false "Refresh token"
+// @Success 200 {object} model.LoginResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req model.RefreshRequest
+	if c.Request.ContentLength > 0 || !h.cookies.Enabled() {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+			return
+		}
+	}
+	if req.RefreshToken == "" && h.cookies.Enabled() {
+		req.RefreshToken, _ = c.Cookie(middleware.RefreshTokenCookie)
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		if err.Error() == "invalid refresh token" || err.Error() == "refresh token reuse detected" {
+			h.cookies.Clear(c)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	writeLoginResponse(c, h.cookies, response)
+}
+
+// CSRFToken handles fetching the CSRF token of the cookie auth mode
+// @Summary Get CSRF token
+// @Description Return the CSRF token to send in the X-CSRF-Token header, setting the csrf_token cookie if needed
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/csrf [get]
+func (h *AuthHandler) CSRFToken(c *gin.Context) {
+	if !h.cookies.Enabled() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cookie authentication is disabled"})
+		return
+	}
+
+	token, err := h.cookies.IssueCSRFToken(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"csrf_token": token})
+}
+
+// Logout handles user logout
+// @Summary Logout user
+// @Description Revoke the current access token and optionally its refresh token
+// @Tags auth
+// @Accept json
+// @Security BearerAuth
+// @Param token body model.LogoutRequest false "Refresh token to revoke"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	token, exists := c.Get("token")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token not found in context"})
+		return
+	}
+
+	var req model.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+			return
+		}
+	}
+	if req.RefreshToken == "" && h.cookies.Enabled() {
+		req.RefreshToken, _ = c.Cookie(middleware.RefreshTokenCookie)
+	}
+
+	if err := h.authService.Logout(token.(string), req.RefreshToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	h.cookies.Clear(c)
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll handles revoking every token of the current user
+// @Summary Logout from all devices
+// @Description Revoke every access and refresh token of the current user
+// @Tags auth
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	if err := h.authService.RevokeAllTokens(userID.(string)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	h.cookies.Clear(c)
+	c.Status(http.StatusNoContent)
+}
+
+// ForgotPassword handles password reset requests
+// @Summary Request password reset
+// @Description Email a password reset link if an account exists for the address
+// @Tags auth
+// @Accept json
+// @Param request body model.ForgotPasswordRequest true "Account email"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	if err := h.authService.ForgotPassword(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	// Same response whether or not the account exists
+	c.JSON(http.StatusAccepted, gin.H{"message": "If an account exists for this email, a reset link has been sent"})
+}
+
+// ResetPassword handles setting a new password with a reset token
+// @Summary Reset password
+// @Description Set a new password using a password reset token
+// @Tags auth
+// @Accept json
+// @Param request body model.ResetPasswordRequest true "Reset token and new password"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	if err := h.authService.ResetPassword(req.Token, req.Password); err != nil {
+		if writePasswordPolicyError(c, err) {
+			return
+		}
+		if err.Error() == "invalid or expired reset token" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RequestMagicLink handles passwordless login link requests
+// @Summary Request login link
+// @Description Email a single-use login link if an account exists for the address
+// @Tags auth
+// @Accept json
+// @Param request body model.MagicLinkRequest true "Account email"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/magic-link [post]
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var req model.MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	if err := h.authService.RequestMagicLink(req.Email); err != nil {
+		if writeTooManyRequests(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	// Same response whether or not the account exists
+	c.JSON(http.StatusAccepted, gin.H{"message": "If an account exists for this email, a login link has been sent"})
+}
+
+// MagicLinkLogin handles logging in with a login link
+// @Summary Login with login link
+// @Description Exchange a login link token for an access token. Returns 202 with an MFA challenge if two-factor authentication is enabled.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.MagicLinkLoginRequest true "Login link token"
+// @Success 200 {object} model.LoginResponse
+// @Success 202 {object} model.MFAChallenge
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/magic-link/callback [post]
+func (h *AuthHandler) MagicLinkLogin(c *gin.Context) {
+	var req model.MagicLinkLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, challenge, err := h.authService.LoginWithMagicLink(req.Token, clientInfo(c))
+	if err != nil {
+		if err.Error() == "invalid or expired login link" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "account disabled" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, challenge)
+		return
+	}
+
+	writeLoginResponse(c, h.cookies, response)
+}
+
+// VerifyEmail handles email address verification
+// @Summary Verify email address
+// @Description Confirm ownership of an email address using a verification token
+// @Tags auth
+// @Accept json
+// @Param request body model.VerifyEmailRequest true "Verification token"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req model.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	if err := h.authService.VerifyEmail(req.Token); err != nil {
+		if err.Error() == "invalid or expired verification token" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerification handles resending the verification email
+// @Summary Resend verification email
+// @Description Send a new email verification link to the current user
+// @Tags auth
+// @Security BearerAuth
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/verify-email/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	if err := h.authService.SendVerificationEmail(userID.(string)); err != nil {
+		if err.Error() == "email already verified" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification email sent"})
 }
 
 // Me returns the current authenticated user
@@ -120,3 +487,47 @@ func (h *AuthHandler) Me(c *gin.Context) {
 
 	c.JSON(http.StatusOK, userModel.ToResponse())
 }
+
+// writeTooManyRequests responds with 429 and a Retry-After header if err is a
+// *service.TooManyRequestsError. It reports whether a response was written.
+func writeTooManyRequests(c *gin.Context, err error) bool {
+	var tooMany *service.TooManyRequestsError
+	if !errors.As(err, &tooMany) {
+		return false
+	}
+
+	seconds := int(math.Ceil(tooMany.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": tooMany.Error(), "retry_after": seconds})
+	return true
+}
+
+// writePasswordPolicyError responds with 400 and the list of violations if err
+// is a *service.PasswordPolicyError. It reports whether a response was written.
+func writePasswordPolicyError(c *gin.Context, err error) bool {
+	var policyErr *service.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Password does not meet requirements", "violations": policyErr.Violations})
+	return true
+}
+
+// writeLoginResponse sends a login response, moving the tokens into cookies in cookie auth mode
+func writeLoginResponse(c *gin.Context, cookies *middleware.TokenCookies, response *model.LoginResponse) {
+	if err := cookies.SetTokens(c, response); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// clientInfo describes the client of a request for session records
+func clientInfo(c *gin.Context) model.ClientInfo {
+	return model.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
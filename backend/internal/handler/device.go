@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// DeviceHandler handles OAuth 2.0 device authorization grant requests
+type DeviceHandler struct {
+	deviceService service.DeviceService
+	validator     *validator.Validate
+}
+
+// NewDeviceHandler creates a new device authorization handler
+func NewDeviceHandler(deviceService service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: deviceService,
+		validator:     validator.New(),
+	}
+}
+
+// Authorize handles device authorization requests
+// @Summary Start device authorization
+// @Description Issue a device code and a user code for a client that cannot show a login form (RFC 8628)
+// @Tags auth
+// @Accept x-www-form-urlencoded,json
+// @Produce json
+// @Param client_id formData string false "Client name shown in the session list"
+// @Success 200 {object} model.DeviceCodeResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/device/code [post]
+func (h *DeviceHandler) Authorize(c *gin.Context) {
+	var req model.DeviceCodeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+
+	response, err := h.deviceService.Authorize(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+// Token handles device token polling
+// @Summary Poll for device tokens
+// @Description Exchange an approved device code for an access and refresh token pair. Errors follow RFC 8628: authorization_pending, slow_down, access_denied and expired_token.
+// @Tags auth
+// @Accept x-www-form-urlencoded,json
+// @Produce json
+// @Param grant_type formData string true "urn:ietf:params:oauth:grant-type:device_code"
+// @Param device_code formData string true "Device code"
+// @Success 200 {object} model.DeviceTokenResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/device/token [post]
+func (h *DeviceHandler) Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+
+	var req model.DeviceTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "error_description": err.Error()})
+		return
+	}
+
+	response, err := h.deviceService.PollToken(&req, clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "authorization_pending", "slow_down", "access_denied", "expired_token", "invalid_grant", "unsupported_grant_type":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Decide handles a user approving or denying a device
+// @Summary Approve a device
+// @Description Approve, or deny, the device that shows the given user code. An approved device receives a new session.
+// @Tags auth
+// @Accept json
+// @Security BearerAuth
+// @Param request body model.DeviceApprovalRequest true "User code"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/device/approve [post]
+func (h *DeviceHandler) Decide(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.DeviceApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	if err := h.deviceService.Decide(userID.(string), &req); err != nil {
+		if writeTooManyRequests(c, err) {
+			return
+		}
+		if err.Error() == "invalid or expired user code" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired user code"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
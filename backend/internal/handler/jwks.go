@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// JWKSHandler handles publishing the public token signing keys
+type JWKSHandler struct {
+	keyManager service.KeyManager
+}
+
+// NewJWKSHandler creates a new JWKS handler
+func NewJWKSHandler(keyManager service.KeyManager) *JWKSHandler {
+	return &JWKSHandler{
+		keyManager: keyManager,
+	}
+}
+
+// JWKS returns the JSON Web Key Set used to verify access tokens
+// @Summary Get JSON Web Key Set
+// @Description Get the public keys that verify access tokens issued by this API
+// @Tags auth
+// @Produce json
+// @Success 200 {object} model.JWKS
+// @Failure 500 {object} map[string]interface{}
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	jwks, err := h.keyManager.JWKS()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	// Verifiers may cache the key set briefly; rotated keys stay valid during the grace period
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwks)
+}
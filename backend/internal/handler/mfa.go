@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/middleware"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// MFAHandler handles two-factor authentication related requests
+type MFAHandler struct {
+	mfaService service.MFAService
+	cookies    *middleware.TokenCookies
+	validator  *validator.Validate
+}
+
+// NewMFAHandler creates a new MFA handler
+func NewMFAHandler(mfaService service.MFAService, cookies *middleware.TokenCookies) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+		cookies:    cookies,
+		validator:  validator.New(),
+	}
+}
+
+// Setup handles starting TOTP enrollment
+// @Summary Start TOTP enrollment
+// @Description Generate a new TOTP secret for the current user
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.TOTPSetupResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/2fa/setup [post]
+func (h *MFAHandler) Setup(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	response, err := h.mfaService.Setup(userID.(string))
+	if err != nil {
+		if err.Error() == "two-factor authentication already enabled" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Confirm handles confirming TOTP enrollment
+// @Summary Confirm TOTP enrollment
+// @Description Enable two-factor authentication with a code from the authenticator app and return recovery codes
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.TOTPConfirmRequest true "TOTP code"
+// @Success 200 {object} model.TOTPConfirmResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/2fa/confirm [post]
+func (h *MFAHandler) Confirm(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.TOTPConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, err := h.mfaService.Confirm(userID.(string), req.Code)
+	if err != nil {
+		switch err.Error() {
+		case "two-factor authentication already enabled", "two-factor authentication setup not started", "invalid two-factor code":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Disable handles turning off two-factor authentication
+// @Summary Disable two-factor authentication
+// @Description Disable TOTP for the current user after checking the password and a code
+// @Tags auth
+// @Accept json
+// @Security BearerAuth
+// @Param request body model.TOTPDisableRequest true "Password and TOTP or recovery code"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/2fa/disable [post]
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.TOTPDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	if err := h.mfaService.Disable(userID.(string), c.GetString("session_id"), req.Password, req.Code, c.ClientIP()); err != nil {
+		if writeTooManyRequests(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "two-factor authentication not enabled", "invalid password or two-factor code":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case "recent login required":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Sign in again to confirm this change"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CompleteLogin handles the second step of a two-factor login
+// @Summary Complete two-factor login
+// @Description Exchange an MFA challenge and a TOTP or recovery code for a token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.MFALoginRequest true "MFA challenge and code"
+// @Success 200 {object} model.LoginResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/login/2fa [post]
+func (h *MFAHandler) CompleteLogin(c *gin.Context) {
+	var req model.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, err := h.mfaService.CompleteLogin(req.MFAToken, req.Code, clientInfo(c))
+	if err != nil {
+		if writeTooManyRequests(c, err) {
+			return
+		}
+		if err.Error() == "invalid or expired mfa token" || err.Error() == "invalid two-factor code" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "account disabled" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	writeLoginResponse(c, h.cookies, response)
+}
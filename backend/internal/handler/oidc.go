@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/middleware"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// OIDCHandler handles OpenID Connect single sign-on requests
+type OIDCHandler struct {
+	oidcService service.OIDCService
+	cookies     *middleware.TokenCookies
+	validator   *validator.Validate
+}
+
+// NewOIDCHandler creates a new OpenID Connect handler
+func NewOIDCHandler(oidcService service.OIDCService, cookies *middleware.TokenCookies) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+		cookies:     cookies,
+		validator:   validator.New(),
+	}
+}
+
+// Login handles the start of a single sign-on login
+// @Summary Start single sign-on
+// @Description Redirect to the identity provider using the authorization code flow with PKCE
+// @Tags auth
+// @Success 302
+// @Failure 502 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/oidc/login [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authURL, err := h.oidcService.AuthorizationURL(c.Request.Context())
+	if err != nil {
+		if err.Error() == "single sign-on failed" {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Identity provider unavailable"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback handles the completion of a single sign-on login
+// @Summary Complete single sign-on
+// @Description Exchange the authorization code returned by the identity provider for tokens. Returns 202 with an MFA challenge if two-factor authentication is enabled.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param callback body model.OIDCCallbackRequest true "Authorization response"
+// @Success 200 {object} model.LoginResponse
+// @Success 202 {object} model.MFAChallenge
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/oidc/callback [post]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req model.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, challenge, err := h.oidcService.CompleteLogin(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "invalid or expired login state":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired login state"})
+		case "single sign-on failed":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Single sign-on failed"})
+		case "email not verified by identity provider":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Email address not verified by identity provider"})
+		case "account email not verified":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Verify the email address of your existing account before using single sign-on"})
+		case "account disabled":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		case "signup via single sign-on is disabled":
+			c.JSON(http.StatusForbidden, gin.H{"error": "No account exists for this identity"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, challenge)
+		return
+	}
+
+	writeLoginResponse(c, h.cookies, response)
+}
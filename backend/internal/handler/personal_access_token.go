@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// TokenHandler handles personal access token related requests
+type TokenHandler struct {
+	tokenService service.TokenService
+	validator    *validator.Validate
+}
+
+// NewTokenHandler creates a new personal access token handler
+func NewTokenHandler(tokenService service.TokenService) *TokenHandler {
+	return &TokenHandler{
+		tokenService: tokenService,
+		validator:    validator.New(),
+	}
+}
+
+// Create handles personal access token creation
+// @Summary Create a personal access token
+// @Description Create a scoped token for scripts and integrations. The token is only shown once.
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token body model.CreatePersonalAccessTokenRequest true "Token data"
+// @Success 201 {object} model.CreatePersonalAccessTokenResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/tokens [post]
+func (h *TokenHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.CreatePersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, err := h.tokenService.Create(userID.(string), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// List handles personal access token listing
+// @Summary List personal access tokens
+// @Description Get the active personal access tokens of the current user
+// @Tags tokens
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.PersonalAccessTokenResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/tokens [get]
+func (h *TokenHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	tokens, err := h.tokenService.List(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Revoke handles personal access token revocation
+// @Summary Revoke a personal access token
+// @Description Revoke a personal access token of the current user
+// @Tags tokens
+// @Security BearerAuth
+// @Param id path string true "Token ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/tokens/{id} [delete]
+func (h *TokenHandler) Revoke(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	tokenID := c.Param("id")
+	if tokenID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token ID is required"})
+		return
+	}
+
+	if err := h.tokenService.Revoke(userID.(string), tokenID); err != nil {
+		if err.Error() == "token not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// ReminderHandler handles due date reminder requests
+type ReminderHandler struct {
+	reminderService service.ReminderService
+	validator       *validator.Validate
+}
+
+// NewReminderHandler creates a new reminder handler
+func NewReminderHandler(reminderService service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{
+		reminderService: reminderService,
+		validator:       validator.New(),
+	}
+}
+
+// List handles reminder retrieval
+// @Summary Get reminders
+// @Description Get the reminders of a todo in the order they fire
+// @Tags todos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Todo ID"
+// @Success 200 {array} model.Reminder
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /todos/{id}/reminders [get]
+func (h *ReminderHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	reminders, err := h.reminderService.List(userID.(string), c.Param("id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reminders)
+}
+
+// Create handles adding a reminder
+// @Summary Add reminder
+// @Description Remind the owner of a todo a number of minutes before its due date, e.g. 1440 for one day before or 0 for the due time. Reminders are delivered once through the configured channels.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Todo ID"
+// @Param reminder body model.CreateReminderRequest true "Reminder"
+// @Success 201 {object} model.Reminder
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /todos/{id}/reminders [post]
+func (h *ReminderHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.CreateReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	reminder, err := h.reminderService.Create(userID.(string), c.Param("id"), &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reminder)
+}
+
+// Delete handles reminder deletion
+// @Summary Delete reminder
+// @Description Remove a reminder from a todo
+// @Tags todos
+// @Security BearerAuth
+// @Param id path string true "Todo ID"
+// @Param reminderId path string true "Reminder ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /todos/{id}/reminders/{reminderId} [delete]
+func (h *ReminderHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	if err := h.reminderService.Delete(userID.(string), c.Param("id"), c.Param("reminderId")); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeError maps reminder service errors to responses
+func (h *ReminderHandler) writeError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "todo not found", "reminder not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "reminder already exists":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "too many reminders":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
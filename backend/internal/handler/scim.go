@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// SCIMHandler handles SCIM 2.0 user provisioning requests
+type SCIMHandler struct {
+	scimService service.SCIMService
+	validator   *validator.Validate
+}
+
+// NewSCIMHandler creates a new SCIM handler
+func NewSCIMHandler(scimService service.SCIMService) *SCIMHandler {
+	return &SCIMHandler{
+		scimService: scimService,
+		validator:   validator.New(),
+	}
+}
+
+// CreateUser handles user provisioning
+// @Summary Provision a user
+// @Description Create an account for a directory user. The userName is the account email; the account has no password.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security SCIMAuth
+// @Param user body model.SCIMUser true "SCIM user"
+// @Success 201 {object} model.SCIMUser
+// @Failure 400 {object} model.SCIMError
+// @Failure 401 {object} model.SCIMError
+// @Failure 409 {object} model.SCIMError
+// @Failure 500 {object} model.SCIMError
+// @Router /scim/v2/Users [post]
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var req model.SCIMUser
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewSCIMError(http.StatusBadRequest, "invalidSyntax", err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewSCIMError(http.StatusBadRequest, "invalidValue", err.Error()))
+		return
+	}
+
+	user, err := h.scimService.CreateUser(&req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	resource := user.ToSCIM()
+	c.Header("Location", resource.Meta.Location)
+	c.JSON(http.StatusCreated, resource)
+}
+
+// GetUser handles retrieving a provisioned user
+// @Summary Get a user
+// @Description Get a user by ID in SCIM format
+// @Tags scim
+// @Produce json
+// @Security SCIMAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} model.SCIMUser
+// @Failure 401 {object} model.SCIMError
+// @Failure 404 {object} model.SCIMError
+// @Failure 500 {object} model.SCIMError
+// @Router /scim/v2/Users/{id} [get]
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.scimService.GetUser(userID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToSCIM())
+}
+
+// ListUsers handles listing and filtering users
+// @Summary List users
+// @Description List users oldest first, or find one with a filter such as userName eq "john@example.com"
+// @Tags scim
+// @Produce json
+// @Security SCIMAuth
+// @Param filter query string false "userName or emails equality filter"
+// @Param startIndex query int false "1-based index of the first result" default(1)
+// @Param count query int false "Maximum number of results" default(100)
+// @Success 200 {object} model.SCIMListResponse
+// @Failure 400 {object} model.SCIMError
+// @Failure 401 {object} model.SCIMError
+// @Failure 500 {object} model.SCIMError
+// @Router /scim/v2/Users [get]
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	var req model.SCIMListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewSCIMError(http.StatusBadRequest, "invalidValue", err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewSCIMError(http.StatusBadRequest, "invalidValue", err.Error()))
+		return
+	}
+
+	response, err := h.scimService.ListUsers(&req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// PatchUser handles updating a provisioned user
+// @Summary Update a user
+// @Description Apply add and replace operations to userName, name, displayName and active. Setting active to false disables the account and revokes its tokens.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Security SCIMAuth
+// @Param id path string true "User ID"
+// @Param request body model.SCIMPatchRequest true "Patch operations"
+// @Success 200 {object} model.SCIMUser
+// @Failure 400 {object} model.SCIMError
+// @Failure 401 {object} model.SCIMError
+// @Failure 404 {object} model.SCIMError
+// @Failure 409 {object} model.SCIMError
+// @Failure 500 {object} model.SCIMError
+// @Router /scim/v2/Users/{id} [patch]
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req model.SCIMPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewSCIMError(http.StatusBadRequest, "invalidSyntax", err.Error()))
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.NewSCIMError(http.StatusBadRequest, "invalidSyntax", err.Error()))
+		return
+	}
+
+	user, err := h.scimService.PatchUser(userID, &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user.ToSCIM())
+}
+
+// DeleteUser handles deprovisioning a user
+// @Summary Deprovision a user
+// @Description Disable the account and revoke all of its tokens. The account is kept and can be reactivated by setting active to true.
+// @Tags scim
+// @Security SCIMAuth
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 401 {object} model.SCIMError
+// @Failure 404 {object} model.SCIMError
+// @Failure 500 {object} model.SCIMError
+// @Router /scim/v2/Users/{id} [delete]
+func (h *SCIMHandler) DeleteUser(c *gin.Context) {
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.scimService.DeprovisionUser(userID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// userID returns the user ID path parameter. IDs that are not UUIDs cannot exist,
+// so it writes a 404 response and returns false for them.
+func (h *SCIMHandler) userID(c *gin.Context) (string, bool) {
+	userID := c.Param("id")
+	if err := h.validator.Var(userID, "uuid"); err != nil {
+		c.JSON(http.StatusNotFound, model.NewSCIMError(http.StatusNotFound, "", "User not found"))
+		return "", false
+	}
+	return userID, true
+}
+
+// writeError maps SCIM service errors to SCIM error responses
+func (h *SCIMHandler) writeError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "user not found":
+		c.JSON(http.StatusNotFound, model.NewSCIMError(http.StatusNotFound, "", "User not found"))
+	case "user already exists":
+		c.JSON(http.StatusConflict, model.NewSCIMError(http.StatusConflict, "uniqueness", "A user with this userName already exists"))
+	case "invalid filter":
+		c.JSON(http.StatusBadRequest, model.NewSCIMError(http.StatusBadRequest, "invalidFilter", `Only userName eq "value" and emails eq "value" filters are supported`))
+	case "invalid patch operation":
+		c.JSON(http.StatusBadRequest, model.NewSCIMError(http.StatusBadRequest, "invalidSyntax", "Only add and replace operations are supported"))
+	case "invalid patch value", "invalid user":
+		c.JSON(http.StatusBadRequest, model.NewSCIMError(http.StatusBadRequest, "invalidValue", "Invalid attribute value"))
+	default:
+		c.JSON(http.StatusInternalServerError, model.NewSCIMError(http.StatusInternalServerError, "", "Internal server error"))
+	}
+}
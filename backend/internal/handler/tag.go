@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// TagHandler handles tag related requests
+type TagHandler struct {
+	tagService service.TagService
+	validator  *validator.Validate
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+		validator:  validator.New(),
+	}
+}
+
+// List handles tag listing
+// @Summary List tags
+// @Description Get the current user's tags in alphabetical order
+// @Tags tags
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.Tag
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tags [get]
+func (h *TagHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	tags, err := h.tagService.List(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// Create handles tag creation
+// @Summary Create a tag
+// @Description Create a tag with a name that is unique for the current user and an optional hex color
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tag body model.CreateTagRequest true "Tag creation data"
+// @Success 201 {object} model.Tag
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tags [post]
+func (h *TagHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	tag, err := h.tagService.Create(userID.(string), &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// Update handles tag updates
+// @Summary Update a tag
+// @Description Rename or recolor a tag. Every todo using the tag shows the change.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Param tag body model.UpdateTagRequest true "Tag update data"
+// @Success 200 {object} model.Tag
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tags/{id} [put]
+func (h *TagHandler) Update(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	tag, err := h.tagService.Update(userID.(string), c.Param("id"), &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// Delete handles tag deletion
+// @Summary Delete a tag
+// @Description Delete a tag and remove it from every todo
+// @Tags tags
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /tags/{id} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	if err := h.tagService.Delete(userID.(string), c.Param("id")); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeError maps tag service errors to responses
+func (h *TagHandler) writeError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "tag not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "tag already exists":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case "tag name is required":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
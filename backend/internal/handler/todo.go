@@ -25,7 +25,7 @@ func NewTodoHandler(todoService service.TodoService) *TodoHandler {
 
 // Create handles todo creation
 // @Summary Create a new todo
-// @Description Create a new todo item
+// @Description Create a new todo item. A recurrence rule such as FREQ=WEEKLY;BYDAY=SA repeats the todo from its due date.
 // @Tags todos
 // @Accept json
 // @Produce json
@@ -56,6 +56,11 @@ func (h *TodoHandler) Create(c *gin.Context) {
 
 	todo, err := h.todoService.Create(userID.(string), &req)
 	if err != nil {
+		switch err.Error() {
+		case "tag not found", "project not found", "invalid recurrence rule", "recurrence requires a due date", "recurrence has no occurrences":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 		return
 	}
@@ -74,6 +79,10 @@ func (h *TodoHandler) Create(c *gin.Context) {
 // @Param status query string false "Filter by status" Enums(pending, completed)
 // @Param priority query string false "Filter by priority" Enums(low, medium, high)
 // @Param search query string false "Search in title and description"
+// @Param tag query []string false "Filter by tag name; repeat for several tags" collectionFormat(multi)
+// @Param tag_mode query string false "Match todos with any or all of the tags" Enums(any, all) default(any)
+// @Param project_id query string false "Filter by project"
+// @Param include_archived query bool false "Include todos of archived projects" default(false)
 // @Success 200 {object} model.TodoListResponse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
@@ -147,7 +156,7 @@ func (h *TodoHandler) GetByID(c *gin.Context) {
 
 // Update handles todo updates
 // @Summary Update todo
-// @Description Update a specific todo by its ID
+// @Description Update a specific todo by its ID. Completing a recurring todo creates its next occurrence, and an empty recurrence stops the series.
 // @Tags todos
 // @Accept json
 // @Produce json
@@ -190,6 +199,11 @@ func (h *TodoHandler) Update(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
+		switch err.Error() {
+		case "tag not found", "project not found", "invalid recurrence rule", "recurrence requires a due date", "recurrence has no occurrences":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 		return
 	}
@@ -238,7 +252,7 @@ func (h *TodoHandler) Delete(c *gin.Context) {
 
 // ToggleStatus handles todo status toggle
 // @Summary Toggle todo status
-// @Description Toggle the completion status of a todo
+// @Description Toggle the completion status of a todo. Completing a recurring todo creates its next occurrence.
 // @Tags todos
 // @Produce json
 // @Security BearerAuth
@@ -274,3 +288,42 @@ func (h *TodoHandler) ToggleStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, todo.ToResponse(false))
 }
+
+// StopRecurrence handles ending a series
+// @Summary Stop recurrence
+// @Description Stop a recurring todo from repeating. The todo itself is kept.
+// @Tags todos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Todo ID"
+// @Success 200 {object} model.TodoResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /todos/{id}/recurrence [delete]
+func (h *TodoHandler) StopRecurrence(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	todoID := c.Param("id")
+	if todoID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Todo ID is required"})
+		return
+	}
+
+	todo, err := h.todoService.StopRecurrence(userID.(string), todoID)
+	if err != nil {
+		if err.Error() == "todo not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, todo.ToResponse(false))
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// TodoItemHandler handles checklist item requests
+type TodoItemHandler struct {
+	itemService service.TodoItemService
+	validator   *validator.Validate
+}
+
+// NewTodoItemHandler creates a new checklist item handler
+func NewTodoItemHandler(itemService service.TodoItemService) *TodoItemHandler {
+	return &TodoItemHandler{
+		itemService: itemService,
+		validator:   validator.New(),
+	}
+}
+
+// List handles checklist retrieval
+// @Summary Get checklist
+// @Description Get the checklist items of a todo in display order
+// @Tags todos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Todo ID"
+// @Success 200 {array} model.TodoItem
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /todos/{id}/items [get]
+func (h *TodoItemHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	items, err := h.itemService.List(userID.(string), c.Param("id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// Create handles adding a checklist item
+// @Summary Add checklist item
+// @Description Append an item to the checklist of a todo
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Todo ID"
+// @Param item body model.CreateTodoItemRequest true "Checklist item"
+// @Success 201 {object} model.TodoItem
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /todos/{id}/items [post]
+func (h *TodoItemHandler) Create(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.CreateTodoItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	item, err := h.itemService.Create(userID.(string), c.Param("id"), &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// Update handles checklist item updates
+// @Summary Update checklist item
+// @Description Rename a checklist item or mark it as completed. A todo with auto_complete is completed when every item is.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Todo ID"
+// @Param itemId path string true "Checklist item ID"
+// @Param item body model.UpdateTodoItemRequest true "Checklist item update data"
+// @Success 200 {object} model.TodoItem
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /todos/{id}/items/{itemId} [put]
+func (h *TodoItemHandler) Update(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.UpdateTodoItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	item, err := h.itemService.Update(userID.(string), c.Param("id"), c.Param("itemId"), &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// Delete handles checklist item deletion
+// @Summary Delete checklist item
+// @Description Remove an item from the checklist of a todo
+// @Tags todos
+// @Security BearerAuth
+// @Param id path string true "Todo ID"
+// @Param itemId path string true "Checklist item ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /todos/{id}/items/{itemId} [delete]
+func (h *TodoItemHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	if err := h.itemService.Delete(userID.(string), c.Param("id"), c.Param("itemId")); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Reorder handles checklist reordering
+// @Summary Reorder checklist
+// @Description Set the display order of a todo's checklist. Every item must be listed exactly once.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Todo ID"
+// @Param order body model.ReorderTodoItemsRequest true "Item IDs in their new order"
+// @Success 200 {array} model.TodoItem
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /todos/{id}/items/reorder [post]
+func (h *TodoItemHandler) Reorder(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.ReorderTodoItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	items, err := h.itemService.Reorder(userID.(string), c.Param("id"), &req)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// writeError maps checklist service errors to responses
+func (h *TodoItemHandler) writeError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "todo not found", "item not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "checklist is full", "invalid item order":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	}
+}
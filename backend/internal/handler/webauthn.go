@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/middleware"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// WebAuthnHandler handles passkey related requests
+type WebAuthnHandler struct {
+	webauthnService service.WebAuthnService
+	cookies         *middleware.TokenCookies
+	validator       *validator.Validate
+}
+
+// NewWebAuthnHandler creates a new WebAuthn handler
+func NewWebAuthnHandler(webauthnService service.WebAuthnService, cookies *middleware.TokenCookies) *WebAuthnHandler {
+	return &WebAuthnHandler{
+		webauthnService: webauthnService,
+		cookies:         cookies,
+		validator:       validator.New(),
+	}
+}
+
+// BeginRegistration handles starting a passkey registration
+// @Summary Start passkey registration
+// @Description Return the options for navigator.credentials.create
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/webauthn/register/begin [post]
+func (h *WebAuthnHandler) BeginRegistration(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	options, err := h.webauthnService.BeginRegistration(userID.(string))
+	if err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishRegistration handles finishing a passkey registration
+// @Summary Finish passkey registration
+// @Description Verify the new credential returned by the authenticator and store it
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param credential body model.WebAuthnRegistrationRequest true "Passkey name and new credential"
+// @Success 201 {object} model.WebAuthnCredentialResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/webauthn/register/finish [post]
+func (h *WebAuthnHandler) FinishRegistration(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.WebAuthnRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	credential, err := h.webauthnService.FinishRegistration(userID.(string), &req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid passkey response", "invalid or expired challenge":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "passkey already registered":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, credential)
+}
+
+// BeginLogin handles starting a passkey login
+// @Summary Start passkey login
+// @Description Return the options for navigator.credentials.get. Without an email any discoverable passkey can be used.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.WebAuthnLoginBeginRequest false "Account email"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/webauthn/login/begin [post]
+func (h *WebAuthnHandler) BeginLogin(c *gin.Context) {
+	var req model.WebAuthnLoginBeginRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+			return
+		}
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	options, err := h.webauthnService.BeginLogin(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishLogin handles finishing a passkey login
+// @Summary Finish passkey login
+// @Description Verify the assertion returned by the authenticator and log in. Returns 202 with an MFA challenge if two-factor authentication is enabled and the passkey did not verify the user.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credential body model.WebAuthnLoginRequest true "Assertion"
+// @Success 200 {object} model.LoginResponse
+// @Success 202 {object} model.MFAChallenge
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/webauthn/login/finish [post]
+func (h *WebAuthnHandler) FinishLogin(c *gin.Context) {
+	var req model.WebAuthnLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	response, challenge, err := h.webauthnService.FinishLogin(&req, clientInfo(c))
+	if err != nil {
+		switch err.Error() {
+		case "invalid passkey response", "invalid or expired challenge":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "invalid passkey":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid passkey"})
+		case "account disabled":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, challenge)
+		return
+	}
+
+	writeLoginResponse(c, h.cookies, response)
+}
+
+// ListCredentials handles listing the current user's passkeys
+// @Summary List passkeys
+// @Description Get the passkeys registered by the current user
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.WebAuthnCredentialResponse
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/webauthn/credentials [get]
+func (h *WebAuthnHandler) ListCredentials(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	credentials, err := h.webauthnService.ListCredentials(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, credentials)
+}
+
+// RenameCredential handles renaming a passkey
+// @Summary Rename a passkey
+// @Description Change the name of one of the current user's passkeys
+// @Tags auth
+// @Accept json
+// @Security BearerAuth
+// @Param id path string true "Passkey ID"
+// @Param request body model.RenameWebAuthnCredentialRequest true "New name"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/webauthn/credentials/{id} [patch]
+func (h *WebAuthnHandler) RenameCredential(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	var req model.RenameWebAuthnCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "details": err.Error()})
+		return
+	}
+
+	if err := h.webauthnService.RenameCredential(userID.(string), c.Param("id"), &req); err != nil {
+		if err.Error() == "passkey not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Passkey not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteCredential handles deleting a passkey
+// @Summary Delete a passkey
+// @Description Remove one of the current user's passkeys. It can no longer be used to log in.
+// @Tags auth
+// @Security BearerAuth
+// @Param id path string true "Passkey ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /auth/webauthn/credentials/{id} [delete]
+func (h *WebAuthnHandler) DeleteCredential(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	if err := h.webauthnService.DeleteCredential(userID.(string), c.Param("id")); err != nil {
+		if err.Error() == "passkey not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Passkey not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,126 @@
+package mailer
+
+import (
+	"fmt"
+	"io"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+)
+
+// Message represents an email message
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer defines the interface for sending email
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// format renders a message as a plain text RFC 5322 email
+func format(from string, msg *Message) string {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	return b.String()
+}
+
+// writerMailer implements Mailer by writing messages to a writer
+type writerMailer struct {
+	mu   sync.Mutex
+	from string
+	w    io.Writer
+}
+
+// NewStdoutMailer creates a mailer that prints messages to standard output
+func NewStdoutMailer(from string) Mailer {
+	return &writerMailer{from: from, w: os.Stdout}
+}
+
+// Send writes the message to the underlying writer
+func (m *writerMailer) Send(msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.w, "----- mail -----\r\n%s\r\n----- end mail -----\r\n", format(m.from, msg))
+	return err
+}
+
+// fileMailer implements Mailer by storing messages in an outbox directory
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer creates a mailer that stores each message as an .eml file in dir
+func NewFileMailer(from, dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileMailer{from: from, dir: dir}, nil
+}
+
+// Send stores the message in the outbox directory
+func (m *fileMailer) Send(msg *Message) error {
+	suffix, err := security.RandomToken(6)
+	if err != nil {
+		return err
+	}
+
+	name := time.Now().UTC().Format("20060102T150405.000000000") + "-" + suffix + ".eml"
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(format(m.from, msg)), 0o600)
+}
+
+// smtpMailer implements Mailer using an SMTP server
+type smtpMailer struct {
+	from     string
+	sender   string
+	address  string
+	host     string
+	username string
+	password string
+}
+
+// NewSMTPMailer creates a mailer that delivers messages through an SMTP server.
+// The from address may include a display name such as "Todo App <noreply@example.com>".
+func NewSMTPMailer(from, host string, port int, username, password string) (Mailer, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+
+	return &smtpMailer{
+		from:     from,
+		sender:   sender.Address,
+		address:  host + ":" + strconv.Itoa(port),
+		host:     host,
+		username: username,
+		password: password,
+	}, nil
+}
+
+// Send delivers the message through the SMTP server. The display name is only used in
+// the From header; the envelope sender is the bare address.
+func (m *smtpMailer) Send(msg *Message) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+	return smtp.SendMail(m.address, auth, m.sender, []string{msg.To}, []byte(format(m.from, msg)))
+}
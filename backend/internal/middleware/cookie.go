@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+)
+
+// Cookie and header names used by the cookie auth mode
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	CSRFCookie         = "csrf_token"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
+// refreshCookiePath limits the refresh token cookie to the auth endpoints
+const refreshCookiePath = "/api/v1/auth"
+
+// TokenCookies writes and clears the authentication cookies of the cookie auth mode.
+// When the mode is disabled every method is a no-op and tokens stay in response bodies.
+type TokenCookies struct {
+	enabled       bool
+	domain        string
+	secure        bool
+	sameSite      http.SameSite
+	accessMaxAge  int
+	refreshMaxAge int
+}
+
+// NewTokenCookies creates the cookie writer from configuration
+func NewTokenCookies(cfg *config.Config) *TokenCookies {
+	sameSite := http.SameSiteLaxMode
+	switch strings.ToLower(cfg.Auth.CookieSameSite) {
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	}
+
+	return &TokenCookies{
+		enabled:       cfg.Auth.CookieAuth,
+		domain:        cfg.Auth.CookieDomain,
+		secure:        cfg.Auth.CookieSecure || sameSite == http.SameSiteNoneMode,
+		sameSite:      sameSite,
+		accessMaxAge:  cfg.JWT.ExpirationHours * 3600,
+		refreshMaxAge: cfg.JWT.RefreshHours * 3600,
+	}
+}
+
+// Enabled reports whether the cookie auth mode is on
+func (tc *TokenCookies) Enabled() bool {
+	return tc.enabled
+}
+
+// SetTokens moves the tokens of a login response into HttpOnly cookies and
+// adds a CSRF token that the client has to echo in the X-CSRF-Token header
+func (tc *TokenCookies) SetTokens(c *gin.Context, response *model.LoginResponse) error {
+	if !tc.enabled {
+		return nil
+	}
+
+	csrfToken, err := tc.IssueCSRFToken(c)
+	if err != nil {
+		return err
+	}
+
+	tc.set(c, AccessTokenCookie, response.Token, "/", tc.accessMaxAge, true)
+	tc.set(c, RefreshTokenCookie, response.RefreshToken, refreshCookiePath, tc.refreshMaxAge, true)
+
+	response.Token = ""
+	response.RefreshToken = ""
+	response.CSRFToken = csrfToken
+	return nil
+}
+
+// IssueCSRFToken returns the CSRF token of the client, setting a new one if there is none
+func (tc *TokenCookies) IssueCSRFToken(c *gin.Context) (string, error) {
+	if token, err := c.Cookie(CSRFCookie); err == nil && token != "" {
+		return token, nil
+	}
+
+	token, err := security.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	// Readable by scripts so same-origin clients can echo it
+	tc.set(c, CSRFCookie, token, "/", tc.refreshMaxAge, false)
+	return token, nil
+}
+
+// Clear removes the authentication cookies
+func (tc *TokenCookies) Clear(c *gin.Context) {
+	if !tc.enabled {
+		return
+	}
+
+	tc.set(c, AccessTokenCookie, "", "/", -1, true)
+	tc.set(c, RefreshTokenCookie, "", refreshCookiePath, -1, true)
+	tc.set(c, CSRFCookie, "", "/", -1, false)
+}
+
+func (tc *TokenCookies) set(c *gin.Context, name, value, path string, maxAge int, httpOnly bool) {
+	c.SetSameSite(tc.sameSite)
+	c.SetCookie(name, value, maxAge, path, tc.domain, tc.secure, httpOnly)
+}
+
+// CSRFMiddleware creates a middleware that protects cookie-authenticated
+// requests with double-submit CSRF tokens. State-changing requests that carry
+// an authentication cookie and no Authorization header must send the value of
+// the CSRF cookie in the X-CSRF-Token header.
+func CSRFMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		// Browsers never attach Authorization headers on their own
+		if c.GetHeader("Authorization") != "" || !hasAuthCookie(c) {
+			c.Next()
+			return
+		}
+
+		cookie, err := c.Cookie(CSRFCookie)
+		header := c.GetHeader(CSRFHeader)
+		if err != nil || cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid CSRF token"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func hasAuthCookie(c *gin.Context) bool {
+	for _, name := range []string{AccessTokenCookie, RefreshTokenCookie} {
+		if value, err := c.Cookie(name); err == nil && value != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -1,37 +1,84 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
 )
 
-// AuthMiddleware creates a middleware for JWT authentication
-func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
+// AuthMiddleware creates a middleware for JWT authentication that also records session activity.
+// If tokenService is non-nil, personal access tokens are accepted as well
+// and their scopes are stored in the context for RequireScope.
+// If cookies is enabled, the access token cookie is used when there is no Authorization header.
+// If proxyAuth is non-nil, requests without an Authorization header that come from a trusted
+// proxy are authenticated by its identity headers, provisioning unknown users on first sight.
+func AuthMiddleware(authService service.AuthService, sessionService service.SessionService, tokenService service.TokenService, cookies *TokenCookies, proxyAuth *ProxyAuth) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		var tokenString string
 		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+		if authHeader != "" {
+			// Extract token from "Bearer <token>"
+			tokenParts := strings.Split(authHeader, " ")
+			if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+				c.Abort()
+				return
+			}
+			tokenString = tokenParts[1]
+		} else if username, email, ok := proxyAuth.identity(c); ok {
+			authenticateProxyUser(c, proxyAuth, username, email)
 			return
+		} else if cookies != nil && cookies.Enabled() {
+			tokenString, _ = c.Cookie(AccessTokenCookie)
 		}
 
-		// Extract token from "Bearer <token>"
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+		if tokenString == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
 			c.Abort()
 			return
 		}
 
-		tokenString := tokenParts[1]
+		// Personal access tokens carry a fixed prefix
+		if strings.HasPrefix(tokenString, model.PersonalAccessTokenPrefix) {
+			if tokenService == nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Personal access tokens are not accepted here"})
+				c.Abort()
+				return
+			}
+
+			user, scopes, err := tokenService.Authenticate(tokenString)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				c.Abort()
+				return
+			}
+
+			c.Set("user", user)
+			c.Set("user_id", user.ID)
+			c.Set("scopes", scopes)
+			c.Next()
+			return
+		}
 
 		// Validate token and get user
-		user, err := authService.GetUserFromToken(tokenString)
+		user, session, err := authService.AuthenticateToken(tokenString)
 		if err != nil {
+			if err.Error() == "token has been revoked" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+				c.Abort()
+				return
+			}
+			if err.Error() == "account disabled" {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+				c.Abort()
+				return
+			}
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
@@ -40,35 +87,135 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		// Set user in context
 		c.Set("user", user)
 		c.Set("user_id", user.ID)
+		c.Set("token", tokenString)
+		if session != nil {
+			c.Set("session_id", session.ID)
+			if err := sessionService.Touch(session); err != nil {
+				log.Printf("Failed to update session %s: %v", session.ID, err)
+			}
+		}
+		c.Next()
+	}
+}
+
+// authenticateProxyUser sets the user asserted by a trusted proxy in the context
+func authenticateProxyUser(c *gin.Context, proxyAuth *ProxyAuth, username, email string) {
+	if isCrossSiteWrite(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Cross-site request rejected"})
+		c.Abort()
+		return
+	}
+
+	user, err := proxyAuth.proxyAuthService.Authenticate(username, email)
+	if err != nil {
+		switch err.Error() {
+		case "account disabled":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		case "user not provisioned":
+			c.JSON(http.StatusForbidden, gin.H{"error": "User not provisioned"})
+		case "account email not verified":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account email not verified"})
+		case "invalid proxy identity":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid proxy identity"})
+		default:
+			log.Printf("Failed to authenticate proxy user: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		c.Abort()
+		return
+	}
+
+	c.Set("user", user)
+	c.Set("user_id", user.ID)
+	c.Next()
+}
+
+// RequireScope creates a middleware that checks a personal access token was granted a scope.
+// Requests authenticated with a JWT have every scope. It must run after AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, exists := c.Get("scopes")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		granted, ok := scopes.([]string)
+		if !ok || !slices.Contains(granted, scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient scope", "required_scope": scope})
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
 
-// CORSMiddleware creates a middleware for handling CORS
+// RequireVerifiedEmail creates a middleware that rejects users who have not verified their email.
+// It must run after AuthMiddleware.
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+			c.Abort()
+			return
+		}
+
+		userModel, ok := user.(*model.User)
+		if !ok || !userModel.IsEmailVerified() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Email address not verified"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole creates a middleware that only lets users with one of the given roles through.
+// The role is read from the user loaded by AuthMiddleware, so a demotion applies immediately.
+func RequireRole(roles ...model.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found in context"})
+			c.Abort()
+			return
+		}
+
+		userModel, ok := user.(*model.User)
+		if !ok || !slices.Contains(roles, userModel.Role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient role"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CORSMiddleware creates a middleware for handling CORS.
+// Credentials are only allowed for explicitly listed origins; an origin that
+// only matches "*" gets a wildcard response so it cannot send cookies.
 func CORSMiddleware(allowOrigins []string, allowMethods []string, allowHeaders []string, allowCredentials bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
+		c.Header("Vary", "Origin")
 
 		// Check if origin is allowed
-		allowed := false
-		for _, allowedOrigin := range allowOrigins {
-			if allowedOrigin == "*" || allowedOrigin == origin {
-				allowed = true
-				break
-			}
-		}
-
-		if allowed {
+		if origin != "" && slices.Contains(allowOrigins, origin) {
 			c.Header("Access-Control-Allow-Origin", origin)
+			if allowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		} else if slices.Contains(allowOrigins, "*") {
+			c.Header("Access-Control-Allow-Origin", "*")
 		}
 
 		c.Header("Access-Control-Allow-Methods", strings.Join(allowMethods, ", "))
 		c.Header("Access-Control-Allow-Headers", strings.Join(allowHeaders, ", "))
 
-		if allowCredentials {
-			c.Header("Access-Control-Allow-Credentials", "true")
-		}
-
 		// Handle preflight requests
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/service"
+)
+
+// ProxyAuth reads the identity asserted by an authenticating reverse proxy.
+// Identity headers are only trusted on connections coming straight from one of
+// the configured networks; anywhere else they are ignored.
+type ProxyAuth struct {
+	proxyAuthService service.ProxyAuthService
+	networks         []*net.IPNet
+	userHeader       string
+	emailHeader      string
+}
+
+// NewProxyAuth creates the proxy identity reader from configuration.
+// It returns nil when the proxy auth mode is disabled.
+func NewProxyAuth(proxyAuthService service.ProxyAuthService, cfg *config.Config) (*ProxyAuth, error) {
+	if !cfg.ProxyAuth.Enabled {
+		return nil, nil
+	}
+
+	var networks []*net.IPNet
+	for _, cidr := range cfg.ProxyAuth.TrustedCIDRs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		// Accept bare addresses like gin's trusted proxies do
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy CIDR %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	if len(networks) == 0 {
+		return nil, fmt.Errorf("proxy auth requires at least one trusted CIDR")
+	}
+
+	return &ProxyAuth{
+		proxyAuthService: proxyAuthService,
+		networks:         networks,
+		userHeader:       cfg.ProxyAuth.UserHeader,
+		emailHeader:      cfg.ProxyAuth.EmailHeader,
+	}, nil
+}
+
+// identity returns the asserted username and email, or ok false if the request
+// did not come from a trusted proxy or carries no identity
+func (pa *ProxyAuth) identity(c *gin.Context) (username, email string, ok bool) {
+	if pa == nil || !pa.trusts(c.Request.RemoteAddr) {
+		return "", "", false
+	}
+
+	if pa.userHeader != "" {
+		username = c.GetHeader(pa.userHeader)
+	}
+	if pa.emailHeader != "" {
+		email = c.GetHeader(pa.emailHeader)
+	}
+	return username, email, username != "" || email != ""
+}
+
+// trusts reports whether the direct peer lies in a trusted network.
+// X-Forwarded-For is deliberately not consulted.
+func (pa *ProxyAuth) trusts(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range pa.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCrossSiteWrite reports whether a browser sent a state-changing request from another site.
+// The proxy attaches the identity to every request, so these must not be trusted.
+func isCrossSiteWrite(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return c.GetHeader("Sec-Fetch-Site") == "cross-site"
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+)
+
+// SCIMContentType is the media type of SCIM requests and responses
+const SCIMContentType = "application/scim+json"
+
+// SCIMAuthMiddleware creates a middleware that authenticates SCIM clients with a shared bearer secret.
+// Responses use the SCIM media type and error format.
+func SCIMAuthMiddleware(secret string) gin.HandlerFunc {
+	expected := security.HashToken(secret)
+	return func(c *gin.Context) {
+		c.Header("Content-Type", SCIMContentType)
+
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		// Comparing hashes keeps the comparison constant-time regardless of length
+		if !found || subtle.ConstantTimeCompare([]byte(security.HashToken(token)), []byte(expected)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="scim"`)
+			c.JSON(http.StatusUnauthorized, model.NewSCIMError(http.StatusUnauthorized, "", "Invalid SCIM token"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// Audit log actions
+const (
+	AuditActionUserDisabled  = "user.disabled"
+	AuditActionUserEnabled   = "user.enabled"
+	AuditActionUserTodosRead = "user.todos.read"
+)
+
+// AuditLog records an action taken by an administrator
+type AuditLog struct {
+	ID         string    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ActorID    string    `gorm:"type:uuid;not null;index" json:"actor_id"`
+	Action     string    `gorm:"type:varchar(50);not null;index" json:"action"`
+	TargetType string    `gorm:"type:varchar(50);not null" json:"target_type"`
+	TargetID   string    `gorm:"not null;index" json:"target_id"`
+	IPAddress  string    `gorm:"type:varchar(64)" json:"ip_address"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName returns the table name for AuditLog model
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditActor identifies the administrator performing an action
+type AuditActor struct {
+	UserID    string
+	IPAddress string
+}
+
+// AuditLogListRequest represents the request parameters for listing audit log entries
+type AuditLogListRequest struct {
+	Page     int    `form:"page" validate:"omitempty,min=1" example:"1"`
+	Limit    int    `form:"limit" validate:"omitempty,min=1,max=100" example:"50"`
+	ActorID  string `form:"actor_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TargetID string `form:"target_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Action   string `form:"action" validate:"max=50" example:"user.disabled"`
+}
+
+// AuditLogListResponse represents the response payload for audit log list
+type AuditLogListResponse struct {
+	Data       []AuditLog `json:"data"`
+	Total      int64      `json:"total"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+	TotalPages int        `json:"total_pages"`
+}
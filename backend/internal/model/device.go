@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+)
+
+// DeviceAuthorizationStatus represents the state of a device authorization request
+type DeviceAuthorizationStatus string
+
+const (
+	DeviceAuthorizationPending  DeviceAuthorizationStatus = "pending"
+	DeviceAuthorizationApproved DeviceAuthorizationStatus = "approved"
+	DeviceAuthorizationDenied   DeviceAuthorizationStatus = "denied"
+)
+
+// DeviceAuthorization represents a pending OAuth 2.0 device authorization grant (RFC 8628).
+// The device polls with the device code while the user approves the user code elsewhere.
+type DeviceAuthorization struct {
+	ID              string                    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	DeviceCodeHash  string                    `gorm:"uniqueIndex;not null" json:"-"`
+	UserCode        string                    `gorm:"uniqueIndex;not null" json:"-"`
+	ClientName      string                    `json:"client_name"`
+	Status          DeviceAuthorizationStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	UserID          *string                   `gorm:"type:uuid;index" json:"-"`
+	IntervalSeconds int                       `gorm:"not null" json:"-"`
+	LastPolledAt    *time.Time                `json:"-"`
+	ExpiresAt       time.Time                 `gorm:"not null;index" json:"expires_at"`
+	CreatedAt       time.Time                 `json:"created_at"`
+}
+
+// TableName returns the table name for DeviceAuthorization model
+func (DeviceAuthorization) TableName() string {
+	return "device_authorizations"
+}
+
+// IsExpired returns true if the request can no longer be approved or redeemed
+func (d *DeviceAuthorization) IsExpired() bool {
+	return !time.Now().Before(d.ExpiresAt)
+}
+
+// DeviceCodeRequest represents the request payload for starting a device authorization
+type DeviceCodeRequest struct {
+	ClientID string `form:"client_id" json:"client_id" validate:"max=100" example:"todo-cli"`
+}
+
+// DeviceCodeResponse represents the device authorization response of RFC 8628
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceTokenRequest represents the request payload for polling the token endpoint
+type DeviceTokenRequest struct {
+	GrantType  string `form:"grant_type" json:"grant_type" validate:"required"`
+	DeviceCode string `form:"device_code" json:"device_code" validate:"required"`
+}
+
+// DeviceTokenResponse represents the token response issued once a device has been approved
+type DeviceTokenResponse struct {
+	AccessToken  string       `json:"access_token"`
+	TokenType    string       `json:"token_type"`
+	ExpiresIn    int          `json:"expires_in"`
+	RefreshToken string       `json:"refresh_token"`
+	User         UserResponse `json:"user"`
+}
+
+// DeviceApprovalRequest represents the request payload for approving or denying a device
+type DeviceApprovalRequest struct {
+	UserCode string `json:"user_code" validate:"required,max=20" example:"WDJB-MJHT"`
+	Deny     bool   `json:"deny" example:"false"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+)
+
+// LoginAttempt tracks failed login attempts for a key such as an email address or client IP
+type LoginAttempt struct {
+	Key           string     `gorm:"primaryKey" json:"key"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	WindowStart   time.Time  `gorm:"not null" json:"window_start"`
+	LastFailureAt time.Time  `gorm:"not null" json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+}
+
+// TableName returns the table name for LoginAttempt model
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// IsLocked returns true if the key is locked out at the given time
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+)
+
+// MFARecoveryCode represents a hashed one-time recovery code for two-factor authentication
+type MFARecoveryCode struct {
+	ID        string     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID    string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for MFARecoveryCode model
+func (MFARecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
+
+// MFAChallenge represents the response payload when a login needs a second factor
+type MFAChallenge struct {
+	MFARequired bool      `json:"mfa_required"`
+	MFAToken    string    `json:"mfa_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// MFALoginRequest represents the request payload for completing a two-factor login
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required" example:"eyJhbGciOi..."`
+	Code     string `json:"code" validate:"required,max=32" example:"123456"`
+}
+
+// TOTPSetupResponse represents the response payload for starting TOTP enrollment
+type TOTPSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TOTPConfirmRequest represents the request payload for confirming TOTP enrollment
+type TOTPConfirmRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric" example:"123456"`
+}
+
+// TOTPConfirmResponse represents the response payload for a confirmed TOTP enrollment
+type TOTPConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TOTPDisableRequest represents the request payload for disabling two-factor authentication.
+// Accounts without a password omit Password and disable it shortly after signing in.
+type TOTPDisableRequest struct {
+	Password string `json:"password" example:"password123"`
+	Code     string `json:"code" validate:"required,max=32" example:"123456"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// OIDCLoginState holds the per-login values of an OpenID Connect authorization request
+type OIDCLoginState struct {
+	StateHash    string    `gorm:"primaryKey" json:"-"`
+	CodeVerifier string    `gorm:"not null" json:"-"`
+	Nonce        string    `gorm:"not null" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"-"`
+	CreatedAt    time.Time `json:"-"`
+}
+
+// TableName returns the table name for OIDCLoginState model
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// ExternalIdentity links a user to an account at an external identity provider
+type ExternalIdentity struct {
+	ID        string    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID    string    `gorm:"type:uuid;not null;index" json:"user_id"`
+	Issuer    string    `gorm:"not null;uniqueIndex:idx_external_identity_subject" json:"issuer"`
+	Subject   string    `gorm:"not null;uniqueIndex:idx_external_identity_subject" json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for ExternalIdentity model
+func (ExternalIdentity) TableName() string {
+	return "external_identities"
+}
+
+// OIDCCallbackRequest represents the request payload for completing an OpenID Connect login
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" validate:"required" example:"SplxlOBeZQQYbYS6WxSbIA"`
+	State string `json:"state" validate:"required" example:"af0ifjsldkj"`
+}
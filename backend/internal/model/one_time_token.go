@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+)
+
+// TokenPurpose represents what a one-time token may be used for
+type TokenPurpose string
+
+const (
+	TokenPurposePasswordReset     TokenPurpose = "password_reset"
+	TokenPurposeEmailVerification TokenPurpose = "email_verification"
+	TokenPurposeMagicLink         TokenPurpose = "magic_link"
+)
+
+// OneTimeToken represents a hashed, single-use and time-limited token
+// that is sent to a user out of band
+type OneTimeToken struct {
+	ID        string       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID    string       `gorm:"type:uuid;not null;index" json:"user_id"`
+	Purpose   TokenPurpose `gorm:"type:varchar(30);not null;index" json:"purpose"`
+	TokenHash string       `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time    `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time   `json:"used_at,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// TableName returns the table name for OneTimeToken model
+func (OneTimeToken) TableName() string {
+	return "one_time_tokens"
+}
+
+// IsUsable returns true if the token has not been used and has not expired
+func (t *OneTimeToken) IsUsable() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
+
+// ForgotPasswordRequest represents the request payload for requesting a password reset
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email" example:"john@example.com"`
+}
+
+// ResetPasswordRequest represents the request payload for resetting a password
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required" example:"q3Jx0b..."`
+	Password string `json:"password" validate:"required" example:"staple-battery-horse"`
+}
+
+// MagicLinkRequest represents the request payload for requesting a passwordless login link
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email" example:"john@example.com"`
+}
+
+// MagicLinkLoginRequest represents the request payload for logging in with a login link
+type MagicLinkLoginRequest struct {
+	Token string `json:"token" validate:"required" example:"q3Jx0b..."`
+}
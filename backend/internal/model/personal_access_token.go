@@ -0,0 +1,85 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Scopes that can be granted to personal access tokens
+const (
+	ScopeTodosRead  = "todos:read"
+	ScopeTodosWrite = "todos:write"
+)
+
+// PersonalAccessTokenPrefix marks bearer tokens that are personal access tokens rather than JWTs
+const PersonalAccessTokenPrefix = "tdp_"
+
+// PersonalAccessToken represents a long-lived, user-managed API token for scripts and integrations
+type PersonalAccessToken struct {
+	ID         string     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID     string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name       string     `gorm:"not null" json:"name"`
+	TokenHash  string     `gorm:"uniqueIndex;not null" json:"-"`
+	Prefix     string     `gorm:"type:varchar(20);not null" json:"prefix"`
+	Scopes     string     `gorm:"not null" json:"-"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for PersonalAccessToken model
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// ScopeList returns the scopes granted to the token
+func (t *PersonalAccessToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
+
+// IsUsable returns true if the token has not been revoked and has not expired
+func (t *PersonalAccessToken) IsUsable() bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || time.Now().Before(*t.ExpiresAt)
+}
+
+// CreatePersonalAccessTokenRequest represents the request payload for creating a personal access token
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" validate:"required,min=1,max=100" example:"Backup script"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=todos:read todos:write" example:"todos:read"`
+	ExpiresInDays *int     `json:"expires_in_days,omitempty" validate:"omitempty,min=1,max=365" example:"90"`
+}
+
+// PersonalAccessTokenResponse represents the response payload for personal access token data
+type PersonalAccessTokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ToResponse converts PersonalAccessToken to PersonalAccessTokenResponse
+func (t *PersonalAccessToken) ToResponse() PersonalAccessTokenResponse {
+	return PersonalAccessTokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     t.ScopeList(),
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// CreatePersonalAccessTokenResponse represents the response payload for a newly created token.
+// The plain token is only ever returned here.
+type CreatePersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	Token string `json:"token"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// DefaultProjectColor is used for projects created without a color
+const DefaultProjectColor = "#1e88e5"
+
+// Project represents a user-owned list that groups todos
+type Project struct {
+	ID          string    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID      string    `gorm:"type:uuid;not null;index" json:"-"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Description string    `json:"description"`
+	Color       string    `gorm:"type:varchar(7);not null" json:"color"`
+	Archived    bool      `gorm:"not null;default:false" json:"archived"`
+	Position    int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for Project model
+func (Project) TableName() string {
+	return "projects"
+}
+
+// CreateProjectRequest represents the request payload for creating a project
+type CreateProjectRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=100" example:"Home"`
+	Description string `json:"description" validate:"max=1000" example:"Chores and errands"`
+	Color       string `json:"color" validate:"omitempty,hexcolor" example:"#43a047"`
+}
+
+// UpdateProjectRequest represents the request payload for updating a project
+type UpdateProjectRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=100" example:"Home"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000" example:"Chores and errands"`
+	Color       *string `json:"color,omitempty" validate:"omitempty,hexcolor" example:"#43a047"`
+	Archived    *bool   `json:"archived,omitempty" example:"true"`
+	Position    *int    `json:"position,omitempty" validate:"omitempty,min=0" example:"2"`
+}
+
+// ProjectListRequest represents the request parameters for listing projects
+type ProjectListRequest struct {
+	IncludeArchived bool `form:"include_archived" example:"false"`
+}
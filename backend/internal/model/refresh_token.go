@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+)
+
+// RefreshToken represents a long-lived token used to obtain new access tokens.
+// Tokens issued from the same login share a FamilyID so that the whole chain
+// can be revoked when a rotated token is presented again.
+type RefreshToken struct {
+	ID        string     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID    string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	FamilyID  string     `gorm:"type:uuid;not null;index" json:"family_id"`
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for RefreshToken model
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// IsExpired returns true if the refresh token is past its expiry time
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// RefreshRequest represents the request payload for refreshing a token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required" example:"q3Jx0b..."`
+}
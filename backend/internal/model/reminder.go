@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// MaxTodoReminders is the maximum number of reminders a todo can have
+const MaxTodoReminders = 10
+
+// MaxReminderOffsetMinutes is the earliest a reminder can fire before the due date (four weeks)
+const MaxReminderOffsetMinutes = 4 * 7 * 24 * 60
+
+// Reminder represents a notification sent a fixed number of minutes before the due date
+// of a todo; an offset of 0 fires at the due time. RemindAt follows the due date and is
+// empty while the todo has none. A replica claims a due reminder until ClaimedUntil,
+// which after a failed delivery is also when it is retried; SentAt is set once it has
+// been delivered. Moving the due date schedules it again.
+type Reminder struct {
+	ID            string     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TodoID        string     `gorm:"type:uuid;not null;uniqueIndex:idx_reminders_todo_offset" json:"todo_id"`
+	UserID        string     `gorm:"type:uuid;not null;index" json:"-"`
+	OffsetMinutes int        `gorm:"not null;uniqueIndex:idx_reminders_todo_offset" json:"offset_minutes"`
+	RemindAt      *time.Time `gorm:"index" json:"remind_at,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	ClaimedUntil  *time.Time `json:"-"`
+	Attempts      int        `gorm:"not null;default:0" json:"-"`
+	LastError     string     `json:"-"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName returns the table name for Reminder model
+func (Reminder) TableName() string {
+	return "reminders"
+}
+
+// Schedule sets when the reminder fires for the given due date and marks it as not sent
+func (r *Reminder) Schedule(dueDate *time.Time) {
+	r.SentAt = nil
+	r.ClaimedUntil = nil
+	r.Attempts = 0
+	if dueDate == nil {
+		r.RemindAt = nil
+		return
+	}
+	remindAt := dueDate.Add(-time.Duration(r.OffsetMinutes) * time.Minute)
+	r.RemindAt = &remindAt
+}
+
+// DueReminder is a reminder claimed for delivery together with its todo and the todo's owner
+type DueReminder struct {
+	Reminder Reminder
+	Todo     Todo
+}
+
+// CreateReminderRequest represents the request payload for adding a reminder to a todo.
+// OffsetMinutes is how long before the due date the reminder fires, e.g. 1440 for one day.
+type CreateReminderRequest struct {
+	OffsetMinutes *int `json:"offset_minutes" validate:"required,min=0,max=40320" example:"1440"`
+}
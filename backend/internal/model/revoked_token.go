@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+)
+
+// RevokedToken represents an access token that was revoked before it expired
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey" json:"jti"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for RevokedToken model
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
+
+// UserTokenVersion holds the current token version of a user.
+// Access tokens carrying an older version are rejected, which revokes
+// every token of the user at once.
+type UserTokenVersion struct {
+	UserID    string    `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Version   int64     `gorm:"not null;default:0" json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for UserTokenVersion model
+func (UserTokenVersion) TableName() string {
+	return "user_token_versions"
+}
+
+// LogoutRequest represents the request payload for logging out
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" example:"q3Jx0b..."`
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// SCIM 2.0 schema URIs (RFC 7643, RFC 7644)
+const (
+	SCIMSchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMSchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMSchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMSchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// SCIMUsersPath is the path of the SCIM Users endpoint, used for resource locations
+const SCIMUsersPath = "/scim/v2/Users"
+
+// SCIMName represents the name of a SCIM user
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty" validate:"max=100" example:"John Doe"`
+	GivenName  string `json:"givenName,omitempty" validate:"max=100" example:"John"`
+	FamilyName string `json:"familyName,omitempty" validate:"max=100" example:"Doe"`
+}
+
+// SCIMEmail represents an email address of a SCIM user
+type SCIMEmail struct {
+	Value   string `json:"value" example:"john@example.com"`
+	Type    string `json:"type,omitempty" example:"work"`
+	Primary bool   `json:"primary,omitempty" example:"true"`
+}
+
+// SCIMMeta represents the resource metadata of a SCIM user
+type SCIMMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// SCIMUser represents a user in SCIM format. The userName is the account email.
+type SCIMUser struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	UserName    string      `json:"userName" validate:"required,email" example:"john@example.com"`
+	Name        *SCIMName   `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty" validate:"max=100" example:"John Doe"`
+	Emails      []SCIMEmail `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty" example:"true"`
+	Meta        *SCIMMeta   `json:"meta,omitempty"`
+}
+
+// ToSCIM converts User to SCIMUser
+func (u *User) ToSCIM() SCIMUser {
+	active := !u.IsDisabled()
+	return SCIMUser{
+		Schemas:     []string{SCIMSchemaUser},
+		ID:          u.ID,
+		UserName:    u.Email,
+		Name:        &SCIMName{Formatted: u.Name},
+		DisplayName: u.Name,
+		Emails:      []SCIMEmail{{Value: u.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &SCIMMeta{
+			ResourceType: "User",
+			Created:      u.CreatedAt,
+			LastModified: u.UpdatedAt,
+			Location:     SCIMUsersPath + "/" + u.ID,
+		},
+	}
+}
+
+// SCIMListRequest represents the query parameters for listing SCIM users
+type SCIMListRequest struct {
+	Filter     string `form:"filter" validate:"max=500" example:"userName eq \"john@example.com\""`
+	StartIndex int    `form:"startIndex" example:"1"`
+	Count      *int   `form:"count" validate:"omitempty,min=0" example:"100"`
+}
+
+// SCIMListResponse represents a page of SCIM users
+type SCIMListResponse struct {
+	Schemas      []string   `json:"schemas"`
+	TotalResults int64      `json:"totalResults"`
+	StartIndex   int        `json:"startIndex"`
+	ItemsPerPage int        `json:"itemsPerPage"`
+	Resources    []SCIMUser `json:"Resources"`
+}
+
+// SCIMPatchOperation represents a single operation of a SCIM PATCH request
+type SCIMPatchOperation struct {
+	Op    string          `json:"op" validate:"required" example:"replace"`
+	Path  string          `json:"path,omitempty" example:"active"`
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+}
+
+// SCIMPatchRequest represents the request payload for patching a SCIM user
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations" validate:"required,min=1,dive"`
+}
+
+// SCIMError represents a SCIM error response
+type SCIMError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// NewSCIMError creates a SCIM error response for an HTTP status
+func NewSCIMError(status int, scimType, detail string) SCIMError {
+	return SCIMError{
+		Schemas:  []string{SCIMSchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+)
+
+// SigningKey represents an asymmetric key used to sign JWTs.
+// A key signs new tokens until it is retired and keeps verifying
+// already issued tokens until it expires.
+type SigningKey struct {
+	KID        string     `gorm:"primaryKey" json:"kid"`
+	Algorithm  string     `gorm:"type:varchar(10);not null" json:"alg"`
+	PrivateKey string     `gorm:"type:text;not null" json:"-"`
+	PublicKey  string     `gorm:"type:text;not null" json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RetiredAt  *time.Time `json:"retired_at,omitempty"`
+	ExpiresAt  *time.Time `gorm:"index" json:"expires_at,omitempty"`
+}
+
+// TableName returns the table name for SigningKey model
+func (SigningKey) TableName() string {
+	return "signing_keys"
+}
+
+// IsActive returns true if the key may still sign new tokens
+func (k *SigningKey) IsActive() bool {
+	return k.RetiredAt == nil
+}
+
+// JWK represents a public JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS represents a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
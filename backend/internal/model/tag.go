@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// DefaultTagColor is used for tags created without a color
+const DefaultTagColor = "#9e9e9e"
+
+// Tag represents a user-owned label that can be attached to todos
+type Tag struct {
+	ID        string    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID    string    `gorm:"type:uuid;not null;uniqueIndex:idx_tags_user_name" json:"-"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_user_name" json:"name"`
+	Color     string    `gorm:"type:varchar(7);not null" json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for Tag model
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// CreateTagRequest represents the request payload for creating a tag
+type CreateTagRequest struct {
+	Name  string `json:"name" validate:"required,min=1,max=50" example:"work"`
+	Color string `json:"color" validate:"omitempty,hexcolor" example:"#1e88e5"`
+}
+
+// UpdateTagRequest represents the request payload for renaming or recoloring a tag
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty" validate:"omitempty,min=1,max=50" example:"office"`
+	Color *string `json:"color,omitempty" validate:"omitempty,hexcolor" example:"#43a047"`
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/rrule"
 	"gorm.io/gorm"
 )
 
@@ -23,21 +24,34 @@ const (
 	StatusCompleted Status = "completed"
 )
 
-// Todo represents a todo item in the system
+// UpcomingOccurrences is the number of future occurrences shown for a recurring todo
+const UpcomingOccurrences = 5
+
+// Todo represents a todo item in the system.
+// A recurring todo carries an RRULE and the start of its series; completing it creates
+// the next occurrence, which takes over the rule, and NextOccurrenceID links to it.
 type Todo struct {
-	ID          string         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Title       string         `gorm:"not null" json:"title" validate:"required,min=1,max=200"`
-	Description string         `json:"description" validate:"max=1000"`
-	Priority    Priority       `gorm:"type:varchar(10);default:'medium'" json:"priority" validate:"oneof=low medium high"`
-	Status      Status         `gorm:"type:varchar(20);default:'pending'" json:"status" validate:"oneof=pending completed"`
-	UserID      string         `gorm:"type:uuid;not null;index" json:"user_id"`
-	DueDate     *time.Time     `json:"due_date,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               string         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Title            string         `gorm:"not null" json:"title" validate:"required,min=1,max=200"`
+	Description      string         `json:"description" validate:"max=1000"`
+	Priority         Priority       `gorm:"type:varchar(10);default:'medium'" json:"priority" validate:"oneof=low medium high"`
+	Status           Status         `gorm:"type:varchar(20);default:'pending'" json:"status" validate:"oneof=pending completed"`
+	UserID           string         `gorm:"type:uuid;not null;index" json:"user_id"`
+	ProjectID        *string        `gorm:"type:uuid;index" json:"project_id,omitempty"`
+	DueDate          *time.Time     `json:"due_date,omitempty"`
+	AutoComplete     bool           `gorm:"not null;default:false" json:"auto_complete"`
+	Recurrence       string         `gorm:"type:varchar(255)" json:"recurrence,omitempty"`
+	RecurrenceStart  *time.Time     `json:"recurrence_start,omitempty"`
+	NextOccurrenceID *string        `gorm:"type:uuid" json:"next_occurrence_id,omitempty"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relations
-	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	User      User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Items     []TodoItem `gorm:"foreignKey:TodoID" json:"items,omitempty"`
+	Tags      []Tag      `gorm:"many2many:todo_tags" json:"tags,omitempty"`
+	Reminders []Reminder `gorm:"foreignKey:TodoID" json:"reminders,omitempty"`
 }
 
 // TableName returns the table name for Todo model
@@ -60,49 +74,117 @@ func (t *Todo) MarkAsPending() {
 	t.Status = StatusPending
 }
 
-// CreateTodoRequest represents the request payload for creating a todo
+// RecurrenceRule returns the parsed rule and series start of a recurring todo.
+// It returns false for todos that do not recur.
+func (t *Todo) RecurrenceRule() (*rrule.Rule, time.Time, bool) {
+	if t.Recurrence == "" || t.DueDate == nil {
+		return nil, time.Time{}, false
+	}
+	rule, err := rrule.Parse(t.Recurrence)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	start := *t.DueDate
+	if t.RecurrenceStart != nil {
+		start = *t.RecurrenceStart
+	}
+	return rule, start, true
+}
+
+// StopRecurrence ends the series at this todo
+func (t *Todo) StopRecurrence() {
+	t.Recurrence = ""
+	t.RecurrenceStart = nil
+}
+
+// CreateTodoRequest represents the request payload for creating a todo.
+// Recurrence is an RFC 5545 RRULE and requires a due date, which starts the series.
+// AutoComplete completes the todo once every checklist item is done; it never reopens it.
 type CreateTodoRequest struct {
-	Title       string     `json:"title" validate:"required,min=1,max=200" example:"Buy groceries"`
-	Description string     `json:"description" validate:"max=1000" example:"Buy milk, eggs, and bread"`
-	Priority    Priority   `json:"priority" validate:"oneof=low medium high" example:"medium"`
-	DueDate     *time.Time `json:"due_date,omitempty" example:"2024-02-01T10:00:00Z"`
+	Title        string     `json:"title" validate:"required,min=1,max=200" example:"Buy groceries"`
+	Description  string     `json:"description" validate:"max=1000" example:"Buy milk, eggs, and bread"`
+	Priority     Priority   `json:"priority" validate:"oneof=low medium high" example:"medium"`
+	DueDate      *time.Time `json:"due_date,omitempty" example:"2024-02-01T10:00:00Z"`
+	AutoComplete bool       `json:"auto_complete" example:"true"`
+	TagIDs       []string   `json:"tag_ids,omitempty" validate:"omitempty,max=20,dive,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ProjectID    *string    `json:"project_id,omitempty" validate:"omitempty,eq=|uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Recurrence   string     `json:"recurrence,omitempty" validate:"max=255" example:"FREQ=WEEKLY;BYDAY=SA"`
 }
 
-// UpdateTodoRequest represents the request payload for updating a todo
+// UpdateTodoRequest represents the request payload for updating a todo.
+// TagIDs replaces the todo's tags when present; an empty list removes them all.
+// An empty ProjectID moves the todo out of its project.
+// Recurrence replaces the series rule from this occurrence on; an empty rule stops the series.
 type UpdateTodoRequest struct {
-	Title       *string    `json:"title,omitempty" validate:"omitempty,min=1,max=200" example:"Buy groceries"`
-	Description *string    `json:"description,omitempty" validate:"omitempty,max=1000" example:"Buy milk, eggs, and bread"`
-	Priority    *Priority  `json:"priority,omitempty" validate:"omitempty,oneof=low medium high" example:"high"`
-	Status      *Status    `json:"status,omitempty" validate:"omitempty,oneof=pending completed" example:"completed"`
-	DueDate     *time.Time `json:"due_date,omitempty" example:"2024-02-01T10:00:00Z"`
+	Title        *string    `json:"title,omitempty" validate:"omitempty,min=1,max=200" example:"Buy groceries"`
+	Description  *string    `json:"description,omitempty" validate:"omitempty,max=1000" example:"Buy milk, eggs, and bread"`
+	Priority     *Priority  `json:"priority,omitempty" validate:"omitempty,oneof=low medium high" example:"high"`
+	Status       *Status    `json:"status,omitempty" validate:"omitempty,oneof=pending completed" example:"completed"`
+	DueDate      *time.Time `json:"due_date,omitempty" example:"2024-02-01T10:00:00Z"`
+	AutoComplete *bool      `json:"auto_complete,omitempty" example:"true"`
+	TagIDs       *[]string  `json:"tag_ids,omitempty" validate:"omitempty,max=20,dive,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ProjectID    *string    `json:"project_id,omitempty" validate:"omitempty,eq=|uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Recurrence   *string    `json:"recurrence,omitempty" validate:"omitempty,max=255" example:"FREQ=MONTHLY;BYMONTHDAY=1"`
+}
+
+// TodoRecurrence describes the series of a recurring todo
+type TodoRecurrence struct {
+	Rule            string      `json:"rule" example:"FREQ=WEEKLY;BYDAY=SA"`
+	NextOccurrences []time.Time `json:"next_occurrences"`
 }
 
 // TodoResponse represents the response payload for todo data
 type TodoResponse struct {
-	ID          string        `json:"id"`
-	Title       string        `json:"title"`
-	Description string        `json:"description"`
-	Priority    Priority      `json:"priority"`
-	Status      Status        `json:"status"`
-	UserID      string        `json:"user_id"`
-	DueDate     *time.Time    `json:"due_date,omitempty"`
-	CreatedAt   time.Time     `json:"created_at"`
-	UpdatedAt   time.Time     `json:"updated_at"`
-	User        *UserResponse `json:"user,omitempty"`
+	ID               string          `json:"id"`
+	Title            string          `json:"title"`
+	Description      string          `json:"description"`
+	Priority         Priority        `json:"priority"`
+	Status           Status          `json:"status"`
+	UserID           string          `json:"user_id"`
+	ProjectID        *string         `json:"project_id,omitempty"`
+	DueDate          *time.Time      `json:"due_date,omitempty"`
+	AutoComplete     bool            `json:"auto_complete"`
+	Progress         *TodoProgress   `json:"progress,omitempty"`
+	Recurrence       *TodoRecurrence `json:"recurrence,omitempty"`
+	NextOccurrenceID *string         `json:"next_occurrence_id,omitempty"`
+	Tags             []Tag           `json:"tags,omitempty"`
+	Reminders        []Reminder      `json:"reminders,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	User             *UserResponse   `json:"user,omitempty"`
 }
 
 // ToResponse converts Todo to TodoResponse
 func (t *Todo) ToResponse(includeUser bool) TodoResponse {
 	response := TodoResponse{
-		ID:          t.ID,
-		Title:       t.Title,
-		Description: t.Description,
-		Priority:    t.Priority,
-		Status:      t.Status,
-		UserID:      t.UserID,
-		DueDate:     t.DueDate,
-		CreatedAt:   t.CreatedAt,
-		UpdatedAt:   t.UpdatedAt,
+		ID:               t.ID,
+		Title:            t.Title,
+		Description:      t.Description,
+		Priority:         t.Priority,
+		Status:           t.Status,
+		UserID:           t.UserID,
+		ProjectID:        t.ProjectID,
+		DueDate:          t.DueDate,
+		AutoComplete:     t.AutoComplete,
+		NextOccurrenceID: t.NextOccurrenceID,
+		Tags:             t.Tags,
+		Reminders:        t.Reminders,
+		CreatedAt:        t.CreatedAt,
+		UpdatedAt:        t.UpdatedAt,
+	}
+
+	// Upcoming occurrences follow the current due date
+	if rule, start, ok := t.RecurrenceRule(); ok {
+		response.Recurrence = &TodoRecurrence{
+			Rule:            rule.String(),
+			NextOccurrences: rule.Occurrences(start, *t.DueDate, UpcomingOccurrences),
+		}
+	}
+
+	// Progress is only reported for todos with a checklist
+	if len(t.Items) > 0 {
+		progress := NewTodoProgress(t.Items)
+		response.Progress = &progress
 	}
 
 	if includeUser {
@@ -113,13 +195,18 @@ func (t *Todo) ToResponse(includeUser bool) TodoResponse {
 	return response
 }
 
-// TodoListRequest represents the request parameters for listing todos
+// TodoListRequest represents the request parameters for listing todos.
+// Todos in archived projects are hidden unless their project is requested or IncludeArchived is set.
 type TodoListRequest struct {
-	Page     int       `form:"page" validate:"min=1" example:"1"`
-	Limit    int       `form:"limit" validate:"min=1,max=100" example:"10"`
-	Status   *Status   `form:"status" validate:"omitempty,oneof=pending completed" example:"pending"`
-	Priority *Priority `form:"priority" validate:"omitempty,oneof=low medium high" example:"high"`
-	Search   string    `form:"search" validate:"max=200" example:"groceries"`
+	Page            int       `form:"page" validate:"min=1" example:"1"`
+	Limit           int       `form:"limit" validate:"min=1,max=100" example:"10"`
+	Status          *Status   `form:"status" validate:"omitempty,oneof=pending completed" example:"pending"`
+	Priority        *Priority `form:"priority" validate:"omitempty,oneof=low medium high" example:"high"`
+	Search          string    `form:"search" validate:"max=200" example:"groceries"`
+	Tags            []string  `form:"tag" validate:"omitempty,max=10,dive,min=1,max=50" example:"work"`
+	TagMode         string    `form:"tag_mode" validate:"omitempty,oneof=any all" example:"any"`
+	ProjectID       string    `form:"project_id" validate:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	IncludeArchived bool      `form:"include_archived" example:"false"`
 }
 
 // TodoListResponse represents the response payload for todo list
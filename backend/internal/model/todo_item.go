@@ -0,0 +1,70 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// MaxTodoItems is the maximum number of checklist items a todo can have
+const MaxTodoItems = 100
+
+// TodoItem represents a checklist item under a todo
+type TodoItem struct {
+	ID        string    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TodoID    string    `gorm:"type:uuid;not null;index" json:"todo_id"`
+	UserID    string    `gorm:"type:uuid;not null;index" json:"-"`
+	Title     string    `gorm:"not null" json:"title"`
+	Completed bool      `gorm:"not null;default:false" json:"completed"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for TodoItem model
+func (TodoItem) TableName() string {
+	return "todo_items"
+}
+
+// TodoProgress summarizes how many checklist items of a todo are completed
+type TodoProgress struct {
+	Completed int    `json:"completed" example:"3"`
+	Total     int    `json:"total" example:"5"`
+	Summary   string `json:"summary" example:"3/5"`
+}
+
+// NewTodoProgress counts the completed items of a checklist
+func NewTodoProgress(items []TodoItem) TodoProgress {
+	completed := 0
+	for _, item := range items {
+		if item.Completed {
+			completed++
+		}
+	}
+	return TodoProgress{
+		Completed: completed,
+		Total:     len(items),
+		Summary:   strconv.Itoa(completed) + "/" + strconv.Itoa(len(items)),
+	}
+}
+
+// IsDone returns true if the checklist has items and all of them are completed
+func (p TodoProgress) IsDone() bool {
+	return p.Total > 0 && p.Completed == p.Total
+}
+
+// CreateTodoItemRequest represents the request payload for adding a checklist item
+type CreateTodoItemRequest struct {
+	Title     string `json:"title" validate:"required,min=1,max=200" example:"Buy milk"`
+	Completed bool   `json:"completed" example:"false"`
+}
+
+// UpdateTodoItemRequest represents the request payload for updating a checklist item
+type UpdateTodoItemRequest struct {
+	Title     *string `json:"title,omitempty" validate:"omitempty,min=1,max=200" example:"Buy oat milk"`
+	Completed *bool   `json:"completed,omitempty" example:"true"`
+}
+
+// ReorderTodoItemsRequest represents the request payload for reordering a checklist
+type ReorderTodoItemsRequest struct {
+	ItemIDs []string `json:"item_ids" validate:"required,min=1,max=100,dive,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestTodoRequestProjectIDValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"absent", `{"title":"Buy groceries"}`, false},
+		{"empty removes the project", `{"title":"Buy groceries","project_id":""}`, false},
+		{"uuid", `{"title":"Buy groceries","project_id":"123e4567-e89b-12d3-a456-426614174000"}`, false},
+		{"not a uuid", `{"title":"Buy groceries","project_id":"inbox"}`, true},
+		{"blank", `{"title":"Buy groceries","project_id":" "}`, true},
+	}
+
+	validate := validator.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := map[string]interface{}{
+				"create": &CreateTodoRequest{Priority: PriorityMedium},
+				"update": &UpdateTodoRequest{},
+			}
+			for kind, req := range requests {
+				if err := json.Unmarshal([]byte(tt.body), req); err != nil {
+					t.Fatalf("%s: Unmarshal() error = %v", kind, err)
+				}
+				err := validate.Struct(req)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("%s: Struct() error = %v, wantErr %v", kind, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
@@ -6,15 +6,29 @@ import (
 	"gorm.io/gorm"
 )
 
+// Role represents the access level of a user
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
 // User represents a user in the system
 type User struct {
-	ID        string         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Name      string         `gorm:"not null" json:"name" validate:"required,min=1,max=100"`
-	Email     string         `gorm:"uniqueIndex;not null" json:"email" validate:"required,email"`
-	Password  string         `gorm:"not null" json:"-" validate:"required,min=8"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              string         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name            string         `gorm:"not null" json:"name" validate:"required,min=1,max=100"`
+	Email           string         `gorm:"uniqueIndex;not null" json:"email" validate:"required,email"`
+	Password        string         `gorm:"not null" json:"-" validate:"required,min=8"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at,omitempty"`
+	TOTPSecret      string         `json:"-"`
+	TOTPEnabledAt   *time.Time     `json:"-"`
+	TOTPLastStep    int64          `gorm:"not null;default:0" json:"-"`
+	Role            Role           `gorm:"type:varchar(20);not null;default:'user'" json:"role"`
+	DisabledAt      *time.Time     `json:"disabled_at,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relations
 	Todos []Todo `json:"todos,omitempty"`
@@ -25,33 +39,92 @@ func (User) TableName() string {
 	return "users"
 }
 
+// IsEmailVerified returns true if the user has confirmed their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// HasPassword returns false for accounts created by single sign-on, a proxy or SCIM
+// that have not set a password yet
+func (u *User) HasPassword() bool {
+	return u.Password != ""
+}
+
+// IsAdmin returns true if the user has the admin role
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsDisabled returns true if an administrator has disabled the account
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// IsTOTPEnabled returns true if the user has confirmed TOTP two-factor authentication
+func (u *User) IsTOTPEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
 // UserRequest represents the request payload for user registration
 type UserRequest struct {
 	Name     string `json:"name" validate:"required,min=1,max=100" example:"John Doe"`
 	Email    string `json:"email" validate:"required,email" example:"john@example.com"`
-	Password string `json:"password" validate:"required,min=8" example:"password123"`
+	Password string `json:"password" validate:"required" example:"correct-horse-battery"`
+}
+
+// UpdateProfileRequest represents the request payload for editing the current user's profile
+type UpdateProfileRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100" example:"John Doe"`
+}
+
+// ChangePasswordRequest represents the request payload for changing the current user's password.
+// Accounts without a password omit CurrentPassword and set their first one shortly after signing in.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" example:"password123"`
+	NewPassword     string `json:"new_password" validate:"required" example:"staple-battery-horse"`
+}
+
+// DeleteAccountRequest represents the request payload for deleting the current user's account.
+// Accounts without a password omit Password and delete the account shortly after signing in.
+type DeleteAccountRequest struct {
+	Password string `json:"password" example:"password123"`
 }
 
 // UserResponse represents the response payload for user data
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID               string     `json:"id"`
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	EmailVerifiedAt  *time.Time `json:"email_verified_at,omitempty"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	HasPassword      bool       `json:"has_password"`
+	Role             Role       `json:"role"`
+	DisabledAt       *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 // ToResponse converts User to UserResponse
 func (u *User) ToResponse() UserResponse {
 	return UserResponse{
-		ID:        u.ID,
-		Name:      u.Name,
-		Email:     u.Email,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:               u.ID,
+		Name:             u.Name,
+		Email:            u.Email,
+		EmailVerifiedAt:  u.EmailVerifiedAt,
+		TwoFactorEnabled: u.IsTOTPEnabled(),
+		HasPassword:      u.HasPassword(),
+		Role:             u.Role,
+		DisabledAt:       u.DisabledAt,
+		CreatedAt:        u.CreatedAt,
+		UpdatedAt:        u.UpdatedAt,
 	}
 }
 
+// VerifyEmailRequest represents the request payload for verifying an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required" example:"q3Jx0b..."`
+}
+
 // LoginRequest represents the request payload for user login
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email" example:"john@example.com"`
@@ -60,6 +133,26 @@ type LoginRequest struct {
 
 // LoginResponse represents the response payload for user login
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token        string       `json:"token,omitempty"`
+	RefreshToken string       `json:"refresh_token,omitempty"`
+	CSRFToken    string       `json:"csrf_token,omitempty"`
+	User         UserResponse `json:"user"`
+}
+
+// UserListRequest represents the request parameters for listing users
+type UserListRequest struct {
+	Page     int    `form:"page" validate:"omitempty,min=1" example:"1"`
+	Limit    int    `form:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Search   string `form:"search" validate:"max=200" example:"john"`
+	Role     *Role  `form:"role" validate:"omitempty,oneof=user admin" example:"admin"`
+	Disabled *bool  `form:"disabled" example:"false"`
+}
+
+// UserListResponse represents the response payload for user list
+type UserListResponse struct {
+	Data       []UserResponse `json:"data"`
+	Total      int64          `json:"total"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	TotalPages int            `json:"total_pages"`
 }
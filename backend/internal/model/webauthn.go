@@ -0,0 +1,111 @@
+package model
+
+import (
+	"time"
+)
+
+// WebAuthnCeremony represents the kind of a pending WebAuthn ceremony
+type WebAuthnCeremony string
+
+const (
+	WebAuthnCeremonyRegistration WebAuthnCeremony = "registration"
+	WebAuthnCeremonyLogin        WebAuthnCeremony = "login"
+)
+
+// WebAuthnChallenge holds the challenge of a pending registration or login ceremony.
+// UserID is empty for logins with a discoverable credential.
+type WebAuthnChallenge struct {
+	ChallengeHash string           `gorm:"primaryKey" json:"-"`
+	Ceremony      WebAuthnCeremony `gorm:"type:varchar(20);not null" json:"-"`
+	UserID        *string          `gorm:"type:uuid" json:"-"`
+	ExpiresAt     time.Time        `gorm:"not null;index" json:"-"`
+	CreatedAt     time.Time        `json:"-"`
+}
+
+// TableName returns the table name for WebAuthnChallenge model
+func (WebAuthnChallenge) TableName() string {
+	return "webauthn_challenges"
+}
+
+// WebAuthnCredential represents a passkey registered by a user
+type WebAuthnCredential struct {
+	ID           string     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID       string     `gorm:"type:uuid;not null;index" json:"user_id"`
+	CredentialID string     `gorm:"uniqueIndex;not null" json:"-"`
+	PublicKey    []byte     `gorm:"not null" json:"-"`
+	SignCount    int64      `gorm:"not null;default:0" json:"-"`
+	AAGUID       string     `json:"-"`
+	Transports   string     `json:"-"`
+	Name         string     `gorm:"not null" json:"name"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for WebAuthnCredential model
+func (WebAuthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
+
+// WebAuthnCredentialResponse represents the response payload for a passkey
+type WebAuthnCredentialResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ToResponse converts WebAuthnCredential to WebAuthnCredentialResponse
+func (c *WebAuthnCredential) ToResponse() WebAuthnCredentialResponse {
+	return WebAuthnCredentialResponse{
+		ID:         c.ID,
+		Name:       c.Name,
+		LastUsedAt: c.LastUsedAt,
+		CreatedAt:  c.CreatedAt,
+	}
+}
+
+// WebAuthnAttestationResponse holds the authenticator response of a registration ceremony
+type WebAuthnAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON" validate:"required"`
+	AttestationObject string   `json:"attestationObject" validate:"required"`
+	Transports        []string `json:"transports" validate:"max=10,dive,max=32"`
+}
+
+// WebAuthnAttestationCredential is a new public key credential as serialized by the browser
+type WebAuthnAttestationCredential struct {
+	ID       string                      `json:"id" validate:"required"`
+	Type     string                      `json:"type" validate:"required,eq=public-key"`
+	Response WebAuthnAttestationResponse `json:"response"`
+}
+
+// WebAuthnRegistrationRequest represents the request payload for finishing a passkey registration
+type WebAuthnRegistrationRequest struct {
+	Name       string                        `json:"name" validate:"max=100" example:"MacBook Touch ID"`
+	Credential WebAuthnAttestationCredential `json:"credential"`
+}
+
+// WebAuthnLoginBeginRequest represents the request payload for starting a passkey login.
+// Without an email address any discoverable credential may be used.
+type WebAuthnLoginBeginRequest struct {
+	Email string `json:"email" validate:"omitempty,email" example:"john@example.com"`
+}
+
+// WebAuthnAssertionResponse holds the authenticator response of a login ceremony
+type WebAuthnAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON" validate:"required"`
+	AuthenticatorData string `json:"authenticatorData" validate:"required"`
+	Signature         string `json:"signature" validate:"required"`
+	UserHandle        string `json:"userHandle"`
+}
+
+// WebAuthnLoginRequest represents the request payload for finishing a passkey login
+type WebAuthnLoginRequest struct {
+	ID       string                    `json:"id" validate:"required"`
+	Type     string                    `json:"type" validate:"required,eq=public-key"`
+	Response WebAuthnAssertionResponse `json:"response"`
+}
+
+// RenameWebAuthnCredentialRequest represents the request payload for renaming a passkey
+type RenameWebAuthnCredentialRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100" example:"YubiKey"`
+}
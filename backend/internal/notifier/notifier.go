@@ -0,0 +1,145 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/mailer"
+)
+
+// Notification represents a due reminder of a todo
+type Notification struct {
+	ReminderID string    `json:"reminder_id"`
+	TodoID     string    `json:"todo_id"`
+	Title      string    `json:"title"`
+	DueDate    time.Time `json:"due_date"`
+	RemindAt   time.Time `json:"remind_at"`
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email"`
+	Name       string    `json:"name"`
+}
+
+// Notifier defines the interface for delivering reminders
+type Notifier interface {
+	Notify(n *Notification) error
+}
+
+// logNotifier implements Notifier by writing reminders to the server log
+type logNotifier struct{}
+
+// NewLogNotifier creates a notifier that logs reminders
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+// Notify logs the reminder
+func (logNotifier) Notify(n *Notification) error {
+	log.Printf("Reminder for user %s: %q is due at %s", n.UserID, n.Title, n.DueDate.Format(time.RFC3339))
+	return nil
+}
+
+// emailNotifier implements Notifier by emailing the owner of the todo
+type emailNotifier struct {
+	mailer mailer.Mailer
+}
+
+// NewEmailNotifier creates a notifier that sends reminders through a mailer
+func NewEmailNotifier(m mailer.Mailer) Notifier {
+	return &emailNotifier{mailer: m}
+}
+
+// Notify emails the reminder to the owner of the todo
+func (e *emailNotifier) Notify(n *Notification) error {
+	body := fmt.Sprintf("Hello %s,\n\nThis is a reminder that \"%s\" is due at %s.\n",
+		n.Name, n.Title, n.DueDate.UTC().Format("Mon, 02 Jan 2006 15:04 MST"))
+
+	// The title is user input; keep it on one line and encode it for the header
+	title := strings.NewReplacer("\r", " ", "\n", " ").Replace(n.Title)
+
+	if err := e.mailer.Send(&mailer.Message{
+		To:      n.Email,
+		Subject: mime.QEncoding.Encode("utf-8", "Reminder: "+title),
+		Body:    body,
+	}); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return nil
+}
+
+// webhookNotifier implements Notifier by posting reminders to a URL
+type webhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that posts each reminder as JSON to url.
+// With a secret, the body is signed with HMAC-SHA256 in the X-Reminder-Signature header.
+// A reminder may be posted more than once; consumers de-duplicate on reminder_id and
+// remind_at, which changes when the due date moves.
+func NewWebhookNotifier(url, secret string, timeout time.Duration) Notifier {
+	return &webhookNotifier{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Notify posts the reminder to the webhook URL. Responses other than 2xx are errors.
+func (w *webhookNotifier) Notify(n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(body)
+		req.Header.Set("X-Reminder-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// multiNotifier implements Notifier by delivering through several notifiers
+type multiNotifier []Notifier
+
+// NewMultiNotifier creates a notifier that delivers through every given notifier
+func NewMultiNotifier(notifiers ...Notifier) Notifier {
+	return multiNotifier(notifiers)
+}
+
+// Notify delivers through every notifier, even when some fail, and joins their errors
+func (m multiNotifier) Notify(n *Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,374 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/oidc/oidctest"
+)
+
+const (
+	testClientID     = "todo-app"
+	testClientSecret = "s3cret"
+	testRedirectURL  = "https://todo.example.com/auth/oidc/callback"
+)
+
+var testIdentity = oidctest.Identity{
+	Subject:       "user-123",
+	Email:         "alice@example.com",
+	EmailVerified: true,
+	Name:          "Alice",
+}
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Server) {
+	t.Helper()
+	idp := oidctest.NewServer(testClientID, testClientSecret)
+	t.Cleanup(idp.Close)
+
+	provider := NewProvider(Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+	})
+	return provider, idp
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	provider, idp := newTestProvider(t)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+	if got := u.Scheme + "://" + u.Host + u.Path; got != idp.URL+"/authorize" {
+		t.Errorf("endpoint = %q, want %q", got, idp.URL+"/authorize")
+	}
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          testRedirectURL,
+		"scope":                 "openid email profile",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        CodeChallenge("verifier-1"),
+		"code_challenge_method": "S256",
+	}
+	query := u.Query()
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// RFC 7636 appendix B
+	got := CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("CodeChallenge() = %q, want %q", got, want)
+	}
+}
+
+func TestLoginFlow(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	code, state, err := idp.Authorize(authURL, testIdentity)
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if state != "state-1" {
+		t.Errorf("state = %q, want %q", state, "state-1")
+	}
+
+	idToken, err := provider.Exchange(ctx, code, "verifier-1")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, idToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken() error = %v", err)
+	}
+
+	want := Claims{Subject: "user-123", Email: "alice@example.com", EmailVerified: true, Name: "Alice"}
+	if *claims != want {
+		t.Errorf("VerifyIDToken() = %+v, want %+v", *claims, want)
+	}
+}
+
+func TestExchangeRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier string
+		reuse    bool
+	}{
+		{"wrong code verifier", "verifier-2", false},
+		{"missing code verifier", "", false},
+		{"code used twice", "verifier-1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, idp := newTestProvider(t)
+			ctx := context.Background()
+
+			authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+			if err != nil {
+				t.Fatalf("AuthCodeURL() error = %v", err)
+			}
+			code, _, err := idp.Authorize(authURL, testIdentity)
+			if err != nil {
+				t.Fatalf("Authorize() error = %v", err)
+			}
+
+			if tt.reuse {
+				if _, err := provider.Exchange(ctx, code, tt.verifier); err != nil {
+					t.Fatalf("first Exchange() error = %v", err)
+				}
+			}
+			if _, err := provider.Exchange(ctx, code, tt.verifier); err == nil {
+				t.Error("Exchange() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestExchangeRejectsWrongClientSecret(t *testing.T) {
+	_, idp := newTestProvider(t)
+	provider := NewProvider(Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     testClientID,
+		ClientSecret: "wrong",
+		RedirectURL:  testRedirectURL,
+	})
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	code, _, err := idp.Authorize(authURL, testIdentity)
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if _, err := provider.Exchange(ctx, code, "verifier-1"); err == nil {
+		t.Error("Exchange() error = nil, want an error")
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(claims jwt.MapClaims)
+		want   Claims
+	}{
+		{
+			name:   "valid",
+			modify: func(jwt.MapClaims) {},
+			want:   Claims{Subject: "user-123", Email: "alice@example.com", EmailVerified: true, Name: "Alice"},
+		},
+		{
+			name:   "email_verified as a string",
+			modify: func(claims jwt.MapClaims) { claims["email_verified"] = "true" },
+			want:   Claims{Subject: "user-123", Email: "alice@example.com", EmailVerified: true, Name: "Alice"},
+		},
+		{
+			name:   "unverified email",
+			modify: func(claims jwt.MapClaims) { claims["email_verified"] = false },
+			want:   Claims{Subject: "user-123", Email: "alice@example.com", Name: "Alice"},
+		},
+		{
+			name: "several audiences issued to us",
+			modify: func(claims jwt.MapClaims) {
+				claims["aud"] = []string{testClientID, "other-app"}
+				claims["azp"] = testClientID
+			},
+			want: Claims{Subject: "user-123", Email: "alice@example.com", EmailVerified: true, Name: "Alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, idp := newTestProvider(t)
+
+			claims := idp.IDTokenClaims(testIdentity, "nonce-1")
+			tt.modify(claims)
+
+			got, err := provider.VerifyIDToken(context.Background(), idp.SignIDToken(claims), "nonce-1")
+			if err != nil {
+				t.Fatalf("VerifyIDToken() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("VerifyIDToken() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenRejects(t *testing.T) {
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		nonce  string
+		modify func(claims jwt.MapClaims)
+		sign   func(idp *oidctest.Server, claims jwt.MapClaims) string
+	}{
+		{
+			name:   "nonce mismatch",
+			nonce:  "nonce-2",
+			modify: func(jwt.MapClaims) {},
+		},
+		{
+			name:   "missing nonce",
+			nonce:  "nonce-1",
+			modify: func(claims jwt.MapClaims) { delete(claims, "nonce") },
+		},
+		{
+			name:   "wrong audience",
+			nonce:  "nonce-1",
+			modify: func(claims jwt.MapClaims) { claims["aud"] = "other-app" },
+		},
+		{
+			name:   "missing audience",
+			nonce:  "nonce-1",
+			modify: func(claims jwt.MapClaims) { delete(claims, "aud") },
+		},
+		{
+			name:   "several audiences without azp",
+			nonce:  "nonce-1",
+			modify: func(claims jwt.MapClaims) { claims["aud"] = []string{testClientID, "other-app"} },
+		},
+		{
+			name:  "several audiences issued to another party",
+			nonce: "nonce-1",
+			modify: func(claims jwt.MapClaims) {
+				claims["aud"] = []string{testClientID, "other-app"}
+				claims["azp"] = "other-app"
+			},
+		},
+		{
+			name:   "wrong issuer",
+			nonce:  "nonce-1",
+			modify: func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+		},
+		{
+			name:   "expired",
+			nonce:  "nonce-1",
+			modify: func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() },
+		},
+		{
+			name:   "missing expiry",
+			nonce:  "nonce-1",
+			modify: func(claims jwt.MapClaims) { delete(claims, "exp") },
+		},
+		{
+			name:   "issued in the future",
+			nonce:  "nonce-1",
+			modify: func(claims jwt.MapClaims) { claims["iat"] = time.Now().Add(time.Hour).Unix() },
+		},
+		{
+			name:   "missing subject",
+			nonce:  "nonce-1",
+			modify: func(claims jwt.MapClaims) { delete(claims, "sub") },
+		},
+		{
+			name:   "signed by another key",
+			nonce:  "nonce-1",
+			modify: func(jwt.MapClaims) {},
+			sign: func(idp *oidctest.Server, claims jwt.MapClaims) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+				token.Header["kid"] = oidctest.KeyID
+				signed, _ := token.SignedString(otherKey)
+				return signed
+			},
+		},
+		{
+			name:   "unknown kid",
+			nonce:  "nonce-1",
+			modify: func(jwt.MapClaims) {},
+			sign: func(idp *oidctest.Server, claims jwt.MapClaims) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+				token.Header["kid"] = "other-key"
+				signed, _ := token.SignedString(otherKey)
+				return signed
+			},
+		},
+		{
+			name:   "symmetric algorithm",
+			nonce:  "nonce-1",
+			modify: func(jwt.MapClaims) {},
+			sign: func(idp *oidctest.Server, claims jwt.MapClaims) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+				token.Header["kid"] = oidctest.KeyID
+				signed, _ := token.SignedString([]byte(testClientSecret))
+				return signed
+			},
+		},
+		{
+			name:   "unsigned",
+			nonce:  "nonce-1",
+			modify: func(jwt.MapClaims) {},
+			sign: func(idp *oidctest.Server, claims jwt.MapClaims) string {
+				signed, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+				return signed
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, idp := newTestProvider(t)
+
+			claims := idp.IDTokenClaims(testIdentity, "nonce-1")
+			tt.modify(claims)
+
+			var rawIDToken string
+			if tt.sign != nil {
+				rawIDToken = tt.sign(idp, claims)
+			} else {
+				rawIDToken = idp.SignIDToken(claims)
+			}
+
+			if _, err := provider.VerifyIDToken(context.Background(), rawIDToken, tt.nonce); err == nil {
+				t.Error("VerifyIDToken() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	_, idp := newTestProvider(t)
+
+	// The provider is reachable under a different address than it claims as issuer
+	provider := NewProvider(Config{
+		Issuer:      strings.Replace(idp.Issuer(), "127.0.0.1", "localhost", 1),
+		ClientID:    testClientID,
+		RedirectURL: testRedirectURL,
+	})
+	if _, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-1"); err == nil {
+		t.Error("AuthCodeURL() error = nil, want an error")
+	}
+}
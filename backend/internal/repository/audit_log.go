@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository defines the interface for audit log data operations
+type AuditLogRepository interface {
+	Create(entry *model.AuditLog) error
+	List(req *model.AuditLogListRequest) ([]model.AuditLog, int64, error)
+}
+
+// auditLogRepository implements AuditLogRepository interface
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+// Create records an audit log entry
+func (r *auditLogRepository) Create(entry *model.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// List retrieves audit log entries with pagination and filters, newest first
+func (r *auditLogRepository) List(req *model.AuditLogListRequest) ([]model.AuditLog, int64, error) {
+	var entries []model.AuditLog
+	var total int64
+
+	query := r.db.Model(&model.AuditLog{})
+
+	// Apply filters
+	if req.ActorID != "" {
+		query = query.Where("actor_id = ?", req.ActorID)
+	}
+	if req.TargetID != "" {
+		query = query.Where("target_id = ?", req.TargetID)
+	}
+	if req.Action != "" {
+		query = query.Where("action = ?", req.Action)
+	}
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.Limit).Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// DeviceAuthorizationRepository defines the interface for device authorization data operations
+type DeviceAuthorizationRepository interface {
+	Create(authorization *model.DeviceAuthorization) error
+	GetByDeviceCodeHash(deviceCodeHash string) (*model.DeviceAuthorization, error)
+	GetByUserCode(userCode string) (*model.DeviceAuthorization, error)
+	Decide(id, userID string, status model.DeviceAuthorizationStatus) (bool, error)
+	RecordPoll(id string, polledAt time.Time, intervalSeconds int) error
+	Delete(id string) (bool, error)
+	DeleteExpired(now time.Time) error
+}
+
+// deviceAuthorizationRepository implements DeviceAuthorizationRepository interface
+type deviceAuthorizationRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceAuthorizationRepository creates a new device authorization repository
+func NewDeviceAuthorizationRepository(db *gorm.DB) DeviceAuthorizationRepository {
+	return &deviceAuthorizationRepository{db: db}
+}
+
+// Create stores a new device authorization request
+func (r *deviceAuthorizationRepository) Create(authorization *model.DeviceAuthorization) error {
+	return r.db.Create(authorization).Error
+}
+
+// GetByDeviceCodeHash retrieves a device authorization by the hash of its device code
+func (r *deviceAuthorizationRepository) GetByDeviceCodeHash(deviceCodeHash string) (*model.DeviceAuthorization, error) {
+	var authorization model.DeviceAuthorization
+	err := r.db.Where("device_code_hash = ?", deviceCodeHash).First(&authorization).Error
+	if err != nil {
+		return nil, err
+	}
+	return &authorization, nil
+}
+
+// GetByUserCode retrieves a device authorization by its normalized user code
+func (r *deviceAuthorizationRepository) GetByUserCode(userCode string) (*model.DeviceAuthorization, error) {
+	var authorization model.DeviceAuthorization
+	err := r.db.Where("user_code = ?", userCode).First(&authorization).Error
+	if err != nil {
+		return nil, err
+	}
+	return &authorization, nil
+}
+
+// Decide approves or denies a pending, unexpired device authorization on behalf of a user.
+// It returns false if the request was already decided or has expired.
+func (r *deviceAuthorizationRepository) Decide(id, userID string, status model.DeviceAuthorizationStatus) (bool, error) {
+	result := r.db.Model(&model.DeviceAuthorization{}).
+		Where("id = ? AND status = ? AND expires_at > ?", id, model.DeviceAuthorizationPending, time.Now()).
+		Updates(map[string]interface{}{"status": status, "user_id": userID})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RecordPoll stores when the device last polled and the interval it has to keep
+func (r *deviceAuthorizationRepository) RecordPoll(id string, polledAt time.Time, intervalSeconds int) error {
+	return r.db.Model(&model.DeviceAuthorization{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_polled_at": polledAt, "interval_seconds": intervalSeconds}).Error
+}
+
+// Delete removes a device authorization.
+// It returns false if it was already removed, so a decision is only redeemed once.
+func (r *deviceAuthorizationRepository) Delete(id string) (bool, error) {
+	result := r.db.Where("id = ?", id).Delete(&model.DeviceAuthorization{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteExpired removes device authorizations that were never redeemed
+func (r *deviceAuthorizationRepository) DeleteExpired(now time.Time) error {
+	return r.db.Where("expires_at < ?", now).Delete(&model.DeviceAuthorization{}).Error
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// LoginAttemptStore defines the interface for failed login attempt counters
+type LoginAttemptStore interface {
+	Get(key string) (*model.LoginAttempt, error)
+	RecordFailure(key string, window time.Duration) (*model.LoginAttempt, error)
+	Release(key string) error
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+}
+
+// memoryLoginAttemptStore implements LoginAttemptStore in process memory
+type memoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]*model.LoginAttempt
+	windows  map[string]time.Duration
+}
+
+// NewMemoryLoginAttemptStore creates a new in-memory login attempt store.
+// Counters are not shared between replicas.
+func NewMemoryLoginAttemptStore() LoginAttemptStore {
+	return &memoryLoginAttemptStore{
+		attempts: make(map[string]*model.LoginAttempt),
+		windows:  make(map[string]time.Duration),
+	}
+}
+
+// Get returns the attempts recorded for a key, or nil if there are none
+func (s *memoryLoginAttemptStore) Get(key string) (*model.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	copied := *attempt
+	return &copied, nil
+}
+
+// RecordFailure counts a failed attempt, starting a new window if the current one has passed
+func (s *memoryLoginAttemptStore) RecordFailure(key string, window time.Duration) (*model.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	// Drop stale entries so the map does not grow without bound.
+	// Keys are counted over different windows, so each is judged by its own.
+	for k, a := range s.attempts {
+		keyWindow, ok := s.windows[k]
+		if !ok {
+			keyWindow = window
+		}
+		if now.Sub(a.LastFailureAt) > keyWindow && !a.IsLocked(now) {
+			delete(s.attempts, k)
+			delete(s.windows, k)
+		}
+	}
+	s.windows[key] = window
+
+	attempt, ok := s.attempts[key]
+	if !ok || now.Sub(attempt.WindowStart) > window {
+		attempt = &model.LoginAttempt{Key: key, WindowStart: now}
+		if ok {
+			attempt.LockedUntil = s.attempts[key].LockedUntil
+		}
+		s.attempts[key] = attempt
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = now
+
+	copied := *attempt
+	return &copied, nil
+}
+
+// Release takes back one counted attempt of a key
+func (s *memoryLoginAttemptStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attempt, ok := s.attempts[key]; ok && attempt.Failures > 0 {
+		attempt.Failures--
+	}
+	return nil
+}
+
+// Lock locks a key until the given time
+func (s *memoryLoginAttemptStore) Lock(key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		attempt = &model.LoginAttempt{Key: key, WindowStart: time.Now(), LastFailureAt: time.Now()}
+		s.attempts[key] = attempt
+	}
+	attempt.LockedUntil = &until
+	return nil
+}
+
+// Reset clears the attempts recorded for a key
+func (s *memoryLoginAttemptStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	delete(s.windows, key)
+	return nil
+}
+
+// postgresLoginAttemptStore implements LoginAttemptStore on top of the database
+// so that counters are shared between replicas
+type postgresLoginAttemptStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresLoginAttemptStore creates a new database backed login attempt store
+func NewPostgresLoginAttemptStore(db *gorm.DB) LoginAttemptStore {
+	return &postgresLoginAttemptStore{db: db}
+}
+
+// Get returns the attempts recorded for a key, or nil if there are none
+func (s *postgresLoginAttemptStore) Get(key string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	err := s.db.Where("key = ?", key).First(&attempt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RecordFailure counts a failed attempt, starting a new window if the current one has passed.
+// The counter is updated atomically so concurrent replicas never lose an attempt.
+func (s *postgresLoginAttemptStore) RecordFailure(key string, window time.Duration) (*model.LoginAttempt, error) {
+	now := time.Now()
+	windowStart := now.Add(-window)
+
+	var attempt model.LoginAttempt
+	err := s.db.Raw(`
+		INSERT INTO login_attempts (key, failures, window_start, last_failure_at)
+		VALUES (?, 1, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.window_start < ? THEN 1 ELSE login_attempts.failures + 1 END,
+			window_start = CASE WHEN login_attempts.window_start < ? THEN EXCLUDED.window_start ELSE login_attempts.window_start END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING *`,
+		key, now, now, windowStart, windowStart,
+	).Scan(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// Release takes back one counted attempt of a key
+func (s *postgresLoginAttemptStore) Release(key string) error {
+	return s.db.Model(&model.LoginAttempt{}).
+		Where("key = ? AND failures > 0", key).
+		Update("failures", gorm.Expr("failures - 1")).Error
+}
+
+// Lock locks a key until the given time
+func (s *postgresLoginAttemptStore) Lock(key string, until time.Time) error {
+	now := time.Now()
+	return s.db.Exec(`
+		INSERT INTO login_attempts (key, failures, window_start, last_failure_at, locked_until)
+		VALUES (?, 0, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET locked_until = EXCLUDED.locked_until`,
+		key, now, now, until,
+	).Error
+}
+
+// Reset clears the attempts recorded for a key
+func (s *postgresLoginAttemptStore) Reset(key string) error {
+	return s.db.Where("key = ?", key).Delete(&model.LoginAttempt{}).Error
+}
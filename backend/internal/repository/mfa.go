@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// MFARecoveryCodeRepository defines the interface for recovery code data operations
+type MFARecoveryCodeRepository interface {
+	ReplaceForUser(userID string, codes []model.MFARecoveryCode) error
+	GetUnusedByUserID(userID string) ([]model.MFARecoveryCode, error)
+	MarkUsed(id string) (bool, error)
+	DeleteByUserID(userID string) error
+}
+
+// mfaRecoveryCodeRepository implements MFARecoveryCodeRepository interface
+type mfaRecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewMFARecoveryCodeRepository creates a new recovery code repository
+func NewMFARecoveryCodeRepository(db *gorm.DB) MFARecoveryCodeRepository {
+	return &mfaRecoveryCodeRepository{db: db}
+}
+
+// ReplaceForUser replaces all recovery codes of a user
+func (r *mfaRecoveryCodeRepository) ReplaceForUser(userID string, codes []model.MFARecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// GetUnusedByUserID retrieves the unused recovery codes of a user
+func (r *mfaRecoveryCodeRepository) GetUnusedByUserID(userID string) ([]model.MFARecoveryCode, error) {
+	var codes []model.MFARecoveryCode
+	err := r.db.Where("user_id = ? AND used_at IS NULL", userID).Find(&codes).Error
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// MarkUsed marks an unused recovery code as used.
+// It returns false if the code was already used.
+func (r *mfaRecoveryCodeRepository) MarkUsed(id string) (bool, error) {
+	result := r.db.Model(&model.MFARecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteByUserID deletes all recovery codes of a user
+func (r *mfaRecoveryCodeRepository) DeleteByUserID(userID string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OIDCRepository defines the interface for OpenID Connect login state and identity data operations
+type OIDCRepository interface {
+	CreateLoginState(state *model.OIDCLoginState) error
+	ConsumeLoginState(stateHash string) (*model.OIDCLoginState, error)
+	DeleteExpiredLoginStates(now time.Time) error
+	GetIdentity(issuer, subject string) (*model.ExternalIdentity, error)
+	CreateIdentity(identity *model.ExternalIdentity) error
+}
+
+// oidcRepository implements OIDCRepository interface
+type oidcRepository struct {
+	db *gorm.DB
+}
+
+// NewOIDCRepository creates a new OpenID Connect repository
+func NewOIDCRepository(db *gorm.DB) OIDCRepository {
+	return &oidcRepository{db: db}
+}
+
+// CreateLoginState stores the state of a pending authorization request
+func (r *oidcRepository) CreateLoginState(state *model.OIDCLoginState) error {
+	return r.db.Create(state).Error
+}
+
+// ConsumeLoginState deletes and returns a login state so it can only be used once.
+// It returns gorm.ErrRecordNotFound if the state does not exist.
+func (r *oidcRepository) ConsumeLoginState(stateHash string) (*model.OIDCLoginState, error) {
+	var states []model.OIDCLoginState
+	err := r.db.Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &states[0], nil
+}
+
+// DeleteExpiredLoginStates removes login states that were never completed
+func (r *oidcRepository) DeleteExpiredLoginStates(now time.Time) error {
+	return r.db.Where("expires_at < ?", now).Delete(&model.OIDCLoginState{}).Error
+}
+
+// GetIdentity retrieves an external identity by issuer and subject
+func (r *oidcRepository) GetIdentity(issuer, subject string) (*model.ExternalIdentity, error) {
+	var identity model.ExternalIdentity
+	err := r.db.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// CreateIdentity links an external identity to a user
+func (r *oidcRepository) CreateIdentity(identity *model.ExternalIdentity) error {
+	return r.db.Create(identity).Error
+}
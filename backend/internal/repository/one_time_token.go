@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// OneTimeTokenRepository defines the interface for one-time token data operations
+type OneTimeTokenRepository interface {
+	Create(token *model.OneTimeToken) error
+	GetByHash(purpose model.TokenPurpose, tokenHash string) (*model.OneTimeToken, error)
+	MarkUsed(id string) (bool, error)
+	InvalidateByUserID(userID string, purpose model.TokenPurpose) error
+}
+
+// oneTimeTokenRepository implements OneTimeTokenRepository interface
+type oneTimeTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewOneTimeTokenRepository creates a new one-time token repository
+func NewOneTimeTokenRepository(db *gorm.DB) OneTimeTokenRepository {
+	return &oneTimeTokenRepository{db: db}
+}
+
+// Create creates a new one-time token
+func (r *oneTimeTokenRepository) Create(token *model.OneTimeToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetByHash retrieves a one-time token by purpose and hash
+func (r *oneTimeTokenRepository) GetByHash(purpose model.TokenPurpose, tokenHash string) (*model.OneTimeToken, error) {
+	var token model.OneTimeToken
+	err := r.db.Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed marks an unused token as used.
+// It returns false if the token was already used.
+func (r *oneTimeTokenRepository) MarkUsed(id string) (bool, error) {
+	result := r.db.Model(&model.OneTimeToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateByUserID marks every outstanding token of a user for a purpose as used
+func (r *oneTimeTokenRepository) InvalidateByUserID(userID string, purpose model.TokenPurpose) error {
+	return r.db.Model(&model.OneTimeToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
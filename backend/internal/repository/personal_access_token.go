@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// PersonalAccessTokenRepository defines the interface for personal access token data operations
+type PersonalAccessTokenRepository interface {
+	Create(token *model.PersonalAccessToken) error
+	GetByHash(tokenHash string) (*model.PersonalAccessToken, error)
+	GetByUserID(userID string) ([]model.PersonalAccessToken, error)
+	Revoke(userID, tokenID string) (bool, error)
+	RevokeByUserID(userID string) error
+	UpdateLastUsed(id string, lastUsedAt time.Time) error
+}
+
+// personalAccessTokenRepository implements PersonalAccessTokenRepository interface
+type personalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalAccessTokenRepository creates a new personal access token repository
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+// Create creates a new personal access token
+func (r *personalAccessTokenRepository) Create(token *model.PersonalAccessToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetByHash retrieves a personal access token by its hash
+func (r *personalAccessTokenRepository) GetByHash(tokenHash string) (*model.PersonalAccessToken, error) {
+	var token model.PersonalAccessToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetByUserID retrieves the active personal access tokens of a user
+func (r *personalAccessTokenRepository) GetByUserID(userID string) ([]model.PersonalAccessToken, error) {
+	var tokens []model.PersonalAccessToken
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Revoke revokes a personal access token that belongs to a specific user.
+// It returns false if no such active token exists.
+func (r *personalAccessTokenRepository) Revoke(userID, tokenID string) (bool, error) {
+	result := r.db.Model(&model.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeByUserID revokes every personal access token of a user
+func (r *personalAccessTokenRepository) RevokeByUserID(userID string) error {
+	return r.db.Model(&model.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// UpdateLastUsed records when a token was last used
+func (r *personalAccessTokenRepository) UpdateLastUsed(id string, lastUsedAt time.Time) error {
+	return r.db.Model(&model.PersonalAccessToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", lastUsedAt).Error
+}
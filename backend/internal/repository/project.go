@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// ProjectRepository defines the interface for project data operations
+type ProjectRepository interface {
+	Create(project *model.Project) error
+	GetByUserID(userID string, req *model.ProjectListRequest) ([]model.Project, error)
+	GetUserProjectByID(userID, projectID string) (*model.Project, error)
+	NextPosition(userID string) (int, error)
+	Update(project *model.Project) error
+	Delete(id string) error
+}
+
+// projectRepository implements ProjectRepository interface
+type projectRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectRepository creates a new project repository
+func NewProjectRepository(db *gorm.DB) ProjectRepository {
+	return &projectRepository{db: db}
+}
+
+// Create creates a new project
+func (r *projectRepository) Create(project *model.Project) error {
+	return r.db.Create(project).Error
+}
+
+// GetByUserID retrieves the projects of a user in display order
+func (r *projectRepository) GetByUserID(userID string, req *model.ProjectListRequest) ([]model.Project, error) {
+	var projects []model.Project
+
+	query := r.db.Where("user_id = ?", userID)
+	if !req.IncludeArchived {
+		query = query.Where("archived = ?", false)
+	}
+
+	if err := query.Order("position ASC, created_at ASC").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// GetUserProjectByID retrieves a project by ID that belongs to a specific user
+func (r *projectRepository) GetUserProjectByID(userID, projectID string) (*model.Project, error) {
+	var project model.Project
+	err := r.db.Where("id = ? AND user_id = ?", projectID, userID).First(&project).Error
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// NextPosition returns the position after the last project of a user
+func (r *projectRepository) NextPosition(userID string) (int, error) {
+	var position int
+	err := r.db.Model(&model.Project{}).
+		Where("user_id = ?", userID).
+		Select("COALESCE(MAX(position) + 1, 0)").
+		Scan(&position).Error
+	if err != nil {
+		return 0, err
+	}
+	return position, nil
+}
+
+// Update updates a project
+func (r *projectRepository) Update(project *model.Project) error {
+	return r.db.Save(project).Error
+}
+
+// Delete deletes a project by ID. Its todos are kept and moved out of the project.
+func (r *projectRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&model.Todo{}).
+			Where("project_id = ?", id).
+			Update("project_id", nil).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.Project{}).Error
+	})
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReminderRepository defines the interface for reminder data operations
+type ReminderRepository interface {
+	Create(reminder *model.Reminder) error
+	GetByTodoID(todoID string) ([]model.Reminder, error)
+	GetTodoReminderByID(todoID, reminderID string) (*model.Reminder, error)
+	OffsetExists(todoID string, offsetMinutes int) (bool, error)
+	Delete(id string) error
+	Reschedule(todoID string, dueDate time.Time) error
+	ClaimDue(now, claimedUntil time.Time, maxAttempts, limit int) ([]model.DueReminder, error)
+	MarkSent(reminder *model.Reminder, sentAt time.Time) error
+	MarkFailed(id, lastError string, retryAt time.Time) error
+}
+
+// reminderRepository implements ReminderRepository interface
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+// NewReminderRepository creates a new reminder repository
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// Create creates a new reminder
+func (r *reminderRepository) Create(reminder *model.Reminder) error {
+	return r.db.Create(reminder).Error
+}
+
+// GetByTodoID retrieves the reminders of a todo in the order they fire
+func (r *reminderRepository) GetByTodoID(todoID string) ([]model.Reminder, error) {
+	var reminders []model.Reminder
+	err := orderedReminders(r.db).Where("todo_id = ?", todoID).Find(&reminders).Error
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// GetTodoReminderByID retrieves a reminder by ID that belongs to a specific todo
+func (r *reminderRepository) GetTodoReminderByID(todoID, reminderID string) (*model.Reminder, error) {
+	var reminder model.Reminder
+	err := r.db.Where("id = ? AND todo_id = ?", reminderID, todoID).First(&reminder).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reminder, nil
+}
+
+// OffsetExists reports whether a todo already has a reminder with the offset
+func (r *reminderRepository) OffsetExists(todoID string, offsetMinutes int) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Reminder{}).Where("todo_id = ? AND offset_minutes = ?", todoID, offsetMinutes).Count(&count).Error
+	return count > 0, err
+}
+
+// Delete deletes a reminder by ID
+func (r *reminderRepository) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&model.Reminder{}).Error
+}
+
+// Reschedule moves the reminders of a todo to a new due date. Reminders whose time
+// changes are marked as not sent so they fire again for the new date.
+func (r *reminderRepository) Reschedule(todoID string, dueDate time.Time) error {
+	remindAt := gorm.Expr("?::timestamptz - offset_minutes * interval '1 minute'", dueDate)
+	return r.db.Model(&model.Reminder{}).
+		Where("todo_id = ? AND remind_at IS DISTINCT FROM ?", todoID, remindAt).
+		Updates(map[string]interface{}{"remind_at": remindAt, "sent_at": nil, "claimed_until": nil, "attempts": 0}).Error
+}
+
+// ClaimDue claims up to limit unsent reminders of pending todos that are due, have not
+// used up their attempts and are not claimed by another replica, and returns them with
+// their todos and owners. A claim counts an attempt and lasts until claimedUntil; rows
+// locked by another replica are skipped. Reminders are only marked sent after they have
+// been delivered, so one whose claim runs out, for example because the process stopped
+// while delivering, is claimed again: delivery is at least once, not exactly once.
+func (r *reminderRepository) ClaimDue(now, claimedUntil time.Time, maxAttempts, limit int) ([]model.DueReminder, error) {
+	var reminders []model.Reminder
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		pending := tx.Model(&model.Todo{}).Select("id").Where("status = ?", model.StatusPending)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL AND remind_at <= ? AND todo_id IN (?)", now, pending).
+			Where("(claimed_until IS NULL OR claimed_until <= ?) AND attempts < ?", now, maxAttempts).
+			Order("remind_at ASC").
+			Limit(limit).
+			Find(&reminders).Error
+		if err != nil || len(reminders) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(reminders))
+		for i := range reminders {
+			ids[i] = reminders[i].ID
+			reminders[i].ClaimedUntil = &claimedUntil
+			reminders[i].Attempts++
+		}
+		return tx.Model(&model.Reminder{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"claimed_until": claimedUntil, "attempts": gorm.Expr("attempts + 1")}).Error
+	})
+	if err != nil || len(reminders) == 0 {
+		return nil, err
+	}
+
+	todoIDs := make([]string, len(reminders))
+	for i, reminder := range reminders {
+		todoIDs[i] = reminder.TodoID
+	}
+	var todos []model.Todo
+	if err := r.db.Preload("User").Where("id IN ?", todoIDs).Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	todosByID := make(map[string]model.Todo, len(todos))
+	for _, todo := range todos {
+		todosByID[todo.ID] = todo
+	}
+
+	due := make([]model.DueReminder, 0, len(reminders))
+	for _, reminder := range reminders {
+		// The todo may have been deleted since the claim
+		if todo, ok := todosByID[reminder.TodoID]; ok {
+			due = append(due, model.DueReminder{Reminder: reminder, Todo: todo})
+		}
+	}
+	return due, nil
+}
+
+// MarkSent records that a claimed reminder has been delivered. A reminder whose due date
+// moved while it was being delivered stays scheduled for the new date.
+func (r *reminderRepository) MarkSent(reminder *model.Reminder, sentAt time.Time) error {
+	return r.db.Model(&model.Reminder{}).
+		Where("id = ? AND remind_at = ?", reminder.ID, reminder.RemindAt).
+		Updates(map[string]interface{}{"sent_at": sentAt, "claimed_until": nil, "last_error": ""}).Error
+}
+
+// MarkFailed records why delivering a claimed reminder failed and when it may be claimed again
+func (r *reminderRepository) MarkFailed(id, lastError string, retryAt time.Time) error {
+	return r.db.Model(&model.Reminder{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_error": lastError, "claimed_until": retryAt}).Error
+}
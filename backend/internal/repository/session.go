@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SessionRepository defines the interface for session data operations
+type SessionRepository interface {
+	Create(session *model.Session) error
+	GetByID(id string) (*model.Session, error)
+	GetActiveByUserID(userID string) ([]model.Session, error)
+	Extend(id string, lastSeenAt, expiresAt time.Time) error
+	UpdateLastSeen(id string, lastSeenAt time.Time) error
+	Revoke(userID, sessionID string) (bool, error)
+	RevokeOthers(userID, keepSessionID string) ([]string, error)
+	RevokeByUserID(userID string) error
+}
+
+// sessionRepository implements SessionRepository interface
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create creates a new session
+func (r *sessionRepository) Create(session *model.Session) error {
+	return r.db.Create(session).Error
+}
+
+// GetByID retrieves a session by ID
+func (r *sessionRepository) GetByID(id string) (*model.Session, error) {
+	var session model.Session
+	err := r.db.Where("id = ?", id).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetActiveByUserID retrieves the active sessions of a user, most recently used first
+func (r *sessionRepository) GetActiveByUserID(userID string) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Extend records activity on a session and moves its expiry
+func (r *sessionRepository) Extend(id string, lastSeenAt, expiresAt time.Time) error {
+	return r.db.Model(&model.Session{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_seen_at": lastSeenAt, "expires_at": expiresAt}).Error
+}
+
+// UpdateLastSeen records activity on a session
+func (r *sessionRepository) UpdateLastSeen(id string, lastSeenAt time.Time) error {
+	return r.db.Model(&model.Session{}).
+		Where("id = ?", id).
+		Update("last_seen_at", lastSeenAt).Error
+}
+
+// Revoke revokes a session that belongs to a specific user.
+// It returns false if no such active session exists.
+func (r *sessionRepository) Revoke(userID, sessionID string) (bool, error) {
+	result := r.db.Model(&model.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeOthers revokes every active session of a user except one and returns the revoked IDs
+func (r *sessionRepository) RevokeOthers(userID, keepSessionID string) ([]string, error) {
+	var sessions []model.Session
+	err := r.db.Model(&sessions).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID
+	}
+	return ids, nil
+}
+
+// RevokeByUserID revokes every session of a user
+func (r *sessionRepository) RevokeByUserID(userID string) error {
+	return r.db.Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// SigningKeyRepository defines the interface for signing key data operations
+type SigningKeyRepository interface {
+	Create(key *model.SigningKey) error
+	GetValid() ([]model.SigningKey, error)
+	Retire(kid string, retiredAt, expiresAt time.Time) error
+	DeleteExpired() error
+}
+
+// signingKeyRepository implements SigningKeyRepository interface
+type signingKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewSigningKeyRepository creates a new signing key repository
+func NewSigningKeyRepository(db *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepository{db: db}
+}
+
+// Create creates a new signing key
+func (r *signingKeyRepository) Create(key *model.SigningKey) error {
+	return r.db.Create(key).Error
+}
+
+// GetValid retrieves all keys that can still verify tokens, newest first
+func (r *signingKeyRepository) GetValid() ([]model.SigningKey, error) {
+	var keys []model.SigningKey
+	err := r.db.Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Retire stops a key from signing and sets when it stops verifying
+func (r *signingKeyRepository) Retire(kid string, retiredAt, expiresAt time.Time) error {
+	return r.db.Model(&model.SigningKey{}).
+		Where("kid = ? AND retired_at IS NULL", kid).
+		Updates(map[string]interface{}{"retired_at": retiredAt, "expires_at": expiresAt}).Error
+}
+
+// DeleteExpired deletes keys that no longer verify any token
+func (r *signingKeyRepository) DeleteExpired() error {
+	return r.db.Where("expires_at IS NOT NULL AND expires_at <= ?", time.Now()).Delete(&model.SigningKey{}).Error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// TagRepository defines the interface for tag data operations
+type TagRepository interface {
+	Create(tag *model.Tag) error
+	GetByUserID(userID string) ([]model.Tag, error)
+	GetUserTagByID(userID, tagID string) (*model.Tag, error)
+	GetUserTagsByIDs(userID string, tagIDs []string) ([]model.Tag, error)
+	NameExists(userID, name, excludeID string) (bool, error)
+	Update(tag *model.Tag) error
+	Delete(id string) error
+}
+
+// tagRepository implements TagRepository interface
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// Create creates a new tag
+func (r *tagRepository) Create(tag *model.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+// GetByUserID retrieves the tags of a user in alphabetical order
+func (r *tagRepository) GetByUserID(userID string) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := orderedTags(r.db).Where("user_id = ?", userID).Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetUserTagByID retrieves a tag by ID that belongs to a specific user
+func (r *tagRepository) GetUserTagByID(userID, tagID string) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.Where("id = ? AND user_id = ?", tagID, userID).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetUserTagsByIDs retrieves the tags with the given IDs that belong to a specific user
+func (r *tagRepository) GetUserTagsByIDs(userID string, tagIDs []string) ([]model.Tag, error) {
+	var tags []model.Tag
+	if len(tagIDs) == 0 {
+		return tags, nil
+	}
+	err := orderedTags(r.db).Where("id IN ? AND user_id = ?", tagIDs, userID).Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// NameExists reports whether the user has another tag with the name
+func (r *tagRepository) NameExists(userID, name, excludeID string) (bool, error) {
+	var count int64
+	query := r.db.Model(&model.Tag{}).Where("user_id = ? AND name = ?", userID, name)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Update updates a tag and marks every todo using it as modified
+func (r *tagRepository) Update(tag *model.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(tag).Error; err != nil {
+			return err
+		}
+		return touchTaggedTodos(tx, tag.ID)
+	})
+}
+
+// Delete deletes a tag by ID, detaching it from every todo that uses it
+func (r *tagRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := touchTaggedTodos(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM todo_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.Tag{}).Error
+	})
+}
+
+// touchTaggedTodos bumps updated_at on the todos using a tag so clients syncing by
+// modification time pick up the change
+func touchTaggedTodos(tx *gorm.DB, tagID string) error {
+	return tx.Model(&model.Todo{}).
+		Where("id IN (?)", tx.Table("todo_tags").Select("todo_id").Where("tag_id = ?", tagID)).
+		UpdateColumn("updated_at", time.Now()).Error
+}
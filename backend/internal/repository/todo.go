@@ -3,6 +3,7 @@ package repository
 import (
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TodoRepository defines the interface for todo data operations
@@ -11,6 +12,8 @@ type TodoRepository interface {
 	GetByID(id string) (*model.Todo, error)
 	GetByUserID(userID string, req *model.TodoListRequest) ([]model.Todo, int64, error)
 	Update(todo *model.Todo) error
+	CompleteOccurrence(todo, next *model.Todo) error
+	ReplaceTags(todo *model.Todo, tags []model.Tag) error
 	Delete(id string) error
 	GetUserTodoByID(userID, todoID string) (*model.Todo, error)
 }
@@ -33,7 +36,7 @@ func (r *todoRepository) Create(todo *model.Todo) error {
 // GetByID retrieves a todo by ID
 func (r *todoRepository) GetByID(id string) (*model.Todo, error) {
 	var todo model.Todo
-	err := r.db.Preload("User").Where("id = ?", id).First(&todo).Error
+	err := r.db.Preload("User").Preload("Items", orderedItems).Preload("Tags", orderedTags).Preload("Reminders", orderedReminders).Where("id = ?", id).First(&todo).Error
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +46,7 @@ func (r *todoRepository) GetByID(id string) (*model.Todo, error) {
 // GetUserTodoByID retrieves a todo by ID that belongs to a specific user
 func (r *todoRepository) GetUserTodoByID(userID, todoID string) (*model.Todo, error) {
 	var todo model.Todo
-	err := r.db.Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error
+	err := r.db.Preload("Items", orderedItems).Preload("Tags", orderedTags).Preload("Reminders", orderedReminders).Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +70,23 @@ func (r *todoRepository) GetByUserID(userID string, req *model.TodoListRequest)
 	if req.Search != "" {
 		query = query.Where("title ILIKE ? OR description ILIKE ?", "%"+req.Search+"%", "%"+req.Search+"%")
 	}
+	if len(req.Tags) > 0 {
+		tagged := r.db.Table("todo_tags").
+			Select("todo_tags.todo_id").
+			Joins("JOIN tags ON tags.id = todo_tags.tag_id").
+			Where("tags.user_id = ? AND tags.name IN ?", userID, req.Tags)
+		// All-of matching requires a distinct match for every requested tag
+		if req.TagMode == "all" {
+			tagged = tagged.Group("todo_tags.todo_id").Having("COUNT(DISTINCT tags.id) = ?", len(req.Tags))
+		}
+		query = query.Where("id IN (?)", tagged)
+	}
+	if req.ProjectID != "" {
+		query = query.Where("project_id = ?", req.ProjectID)
+	} else if !req.IncludeArchived {
+		archived := r.db.Model(&model.Project{}).Select("id").Where("user_id = ? AND archived = ?", userID, true)
+		query = query.Where("project_id IS NULL OR project_id NOT IN (?)", archived)
+	}
 
 	// Count total records
 	if err := query.Count(&total).Error; err != nil {
@@ -75,19 +95,54 @@ func (r *todoRepository) GetByUserID(userID string, req *model.TodoListRequest)
 
 	// Apply pagination
 	offset := (req.Page - 1) * req.Limit
-	if err := query.Order("created_at DESC").Offset(offset).Limit(req.Limit).Find(&todos).Error; err != nil {
+	if err := query.Preload("Items", orderedItems).Preload("Tags", orderedTags).Preload("Reminders", orderedReminders).Order("created_at DESC").Offset(offset).Limit(req.Limit).Find(&todos).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return todos, total, nil
 }
 
-// Update updates a todo
+// Update updates a todo. Checklist items are saved through TodoItemRepository.
 func (r *todoRepository) Update(todo *model.Todo) error {
-	return r.db.Save(todo).Error
+	return r.db.Omit(clause.Associations).Save(todo).Error
+}
+
+// CompleteOccurrence saves a completed occurrence of a recurring todo together with
+// the next occurrence, linking the two
+func (r *todoRepository) CompleteOccurrence(todo, next *model.Todo) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		todo.NextOccurrenceID = &next.ID
+		return tx.Omit(clause.Associations).Save(todo).Error
+	})
+}
+
+// ReplaceTags sets the tags attached to a todo
+func (r *todoRepository) ReplaceTags(todo *model.Todo, tags []model.Tag) error {
+	if len(tags) == 0 {
+		return r.db.Model(todo).Association("Tags").Clear()
+	}
+	return r.db.Model(todo).Association("Tags").Replace(tags)
 }
 
 // Delete deletes a todo by ID
 func (r *todoRepository) Delete(id string) error {
 	return r.db.Where("id = ?", id).Delete(&model.Todo{}).Error
 }
+
+// orderedItems loads checklist items in their display order
+func orderedItems(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, created_at ASC")
+}
+
+// orderedReminders loads reminders in the order they fire
+func orderedReminders(db *gorm.DB) *gorm.DB {
+	return db.Order("offset_minutes DESC")
+}
+
+// orderedTags loads tags in alphabetical order
+func orderedTags(db *gorm.DB) *gorm.DB {
+	return db.Order("name ASC")
+}
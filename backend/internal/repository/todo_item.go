@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+)
+
+// TodoItemRepository defines the interface for checklist item data operations
+type TodoItemRepository interface {
+	Create(item *model.TodoItem) error
+	GetByTodoID(todoID string) ([]model.TodoItem, error)
+	GetTodoItemByID(todoID, itemID string) (*model.TodoItem, error)
+	NextPosition(todoID string) (int, error)
+	Update(item *model.TodoItem) error
+	Delete(id string) error
+	Reorder(todoID string, itemIDs []string) error
+}
+
+// todoItemRepository implements TodoItemRepository interface
+type todoItemRepository struct {
+	db *gorm.DB
+}
+
+// NewTodoItemRepository creates a new checklist item repository
+func NewTodoItemRepository(db *gorm.DB) TodoItemRepository {
+	return &todoItemRepository{db: db}
+}
+
+// Create creates a new checklist item
+func (r *todoItemRepository) Create(item *model.TodoItem) error {
+	return r.db.Create(item).Error
+}
+
+// GetByTodoID retrieves the checklist items of a todo in display order
+func (r *todoItemRepository) GetByTodoID(todoID string) ([]model.TodoItem, error) {
+	var items []model.TodoItem
+	err := orderedItems(r.db).Where("todo_id = ?", todoID).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetTodoItemByID retrieves a checklist item by ID that belongs to a specific todo
+func (r *todoItemRepository) GetTodoItemByID(todoID, itemID string) (*model.TodoItem, error) {
+	var item model.TodoItem
+	err := r.db.Where("id = ? AND todo_id = ?", itemID, todoID).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// NextPosition returns the position after the last checklist item of a todo
+func (r *todoItemRepository) NextPosition(todoID string) (int, error) {
+	var position int
+	err := r.db.Model(&model.TodoItem{}).
+		Where("todo_id = ?", todoID).
+		Select("COALESCE(MAX(position) + 1, 0)").
+		Scan(&position).Error
+	if err != nil {
+		return 0, err
+	}
+	return position, nil
+}
+
+// Update updates a checklist item
+func (r *todoItemRepository) Update(item *model.TodoItem) error {
+	return r.db.Save(item).Error
+}
+
+// Delete deletes a checklist item by ID
+func (r *todoItemRepository) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&model.TodoItem{}).Error
+}
+
+// Reorder stores the position of every item of a todo from its index in itemIDs
+func (r *todoItemRepository) Reorder(todoID string, itemIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, itemID := range itemIDs {
+			err := tx.Model(&model.TodoItem{}).
+				Where("id = ? AND todo_id = ?", itemID, todoID).
+				Update("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokenRevocationStore defines the interface for access token revocation
+type TokenRevocationStore interface {
+	Revoke(jti string, expiresAt time.Time) error
+	IsRevoked(jti string) (bool, error)
+	RevokeAllForUser(userID string) error
+	TokenVersion(userID string) (int64, error)
+}
+
+// memoryRevocationStore implements TokenRevocationStore in process memory
+type memoryRevocationStore struct {
+	mu       sync.RWMutex
+	revoked  map[string]time.Time
+	versions map[string]int64
+}
+
+// NewMemoryRevocationStore creates a new in-memory revocation store.
+// Revocations are lost on restart and are not shared between replicas.
+func NewMemoryRevocationStore() TokenRevocationStore {
+	return &memoryRevocationStore{
+		revoked:  make(map[string]time.Time),
+		versions: make(map[string]int64),
+	}
+}
+
+// Revoke revokes a single token until it expires
+func (s *memoryRevocationStore) Revoke(jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop entries whose tokens have expired anyway
+	now := time.Now()
+	for id, exp := range s.revoked {
+		if now.After(exp) {
+			delete(s.revoked, id)
+		}
+	}
+
+	s.revoked[jti] = expiresAt
+	return nil
+}
+
+// IsRevoked reports whether a token has been revoked
+func (s *memoryRevocationStore) IsRevoked(jti string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.revoked[jti]
+	return ok, nil
+}
+
+// RevokeAllForUser revokes every token issued to a user so far
+func (s *memoryRevocationStore) RevokeAllForUser(userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.versions[userID]++
+	return nil
+}
+
+// TokenVersion returns the current token version of a user
+func (s *memoryRevocationStore) TokenVersion(userID string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.versions[userID], nil
+}
+
+// postgresRevocationStore implements TokenRevocationStore on top of the database
+type postgresRevocationStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresRevocationStore creates a new database backed revocation store
+func NewPostgresRevocationStore(db *gorm.DB) TokenRevocationStore {
+	return &postgresRevocationStore{db: db}
+}
+
+// Revoke revokes a single token until it expires
+func (s *postgresRevocationStore) Revoke(jti string, expiresAt time.Time) error {
+	// Drop entries whose tokens have expired anyway
+	if err := s.db.Where("expires_at < ?", time.Now()).Delete(&model.RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	token := &model.RevokedToken{JTI: jti, ExpiresAt: expiresAt}
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+// IsRevoked reports whether a token has been revoked
+func (s *postgresRevocationStore) IsRevoked(jti string) (bool, error) {
+	var count int64
+	err := s.db.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RevokeAllForUser revokes every token issued to a user so far
+func (s *postgresRevocationStore) RevokeAllForUser(userID string) error {
+	version := &model.UserTokenVersion{UserID: userID, Version: 1}
+	return s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"version":    gorm.Expr("user_token_versions.version + 1"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(version).Error
+}
+
+// TokenVersion returns the current token version of a user
+func (s *postgresRevocationStore) TokenVersion(userID string) (int64, error) {
+	var version model.UserTokenVersion
+	err := s.db.Where("user_id = ?", userID).First(&version).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return version.Version, nil
+}
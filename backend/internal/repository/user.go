@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
 	"gorm.io/gorm"
 )
@@ -10,8 +12,14 @@ type UserRepository interface {
 	Create(user *model.User) error
 	GetByID(id string) (*model.User, error)
 	GetByEmail(email string) (*model.User, error)
+	EmailExists(email string) (bool, error)
+	List(req *model.UserListRequest) ([]model.User, int64, error)
+	ListRange(offset, limit int) ([]model.User, int64, error)
+	SetRoleByEmails(emails []string, role model.Role) error
 	Update(user *model.User) error
+	AdvanceTOTPStep(id string, step int64) (bool, error)
 	Delete(id string) error
+	PurgeDeleted(deletedBefore time.Time) (int64, error)
 }
 
 // userRepository implements UserRepository interface
@@ -49,12 +57,153 @@ func (r *userRepository) GetByEmail(email string) (*model.User, error) {
 	return &user, nil
 }
 
+// List retrieves users with pagination and filters
+func (r *userRepository) List(req *model.UserListRequest) ([]model.User, int64, error) {
+	var users []model.User
+	var total int64
+
+	query := r.db.Model(&model.User{})
+
+	// Apply filters
+	if req.Search != "" {
+		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+req.Search+"%", "%"+req.Search+"%")
+	}
+	if req.Role != nil {
+		query = query.Where("role = ?", *req.Role)
+	}
+	if req.Disabled != nil {
+		if *req.Disabled {
+			query = query.Where("disabled_at IS NOT NULL")
+		} else {
+			query = query.Where("disabled_at IS NULL")
+		}
+	}
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.Limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// ListRange retrieves users oldest first starting at an offset, so pages stay stable as users are added
+func (r *userRepository) ListRange(offset, limit int) ([]model.User, int64, error) {
+	var users []model.User
+	var total int64
+
+	query := r.db.Model(&model.User{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit == 0 {
+		return users, total, nil
+	}
+
+	if err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// SetRoleByEmails assigns a role to the users with the given email addresses
+func (r *userRepository) SetRoleByEmails(emails []string, role model.Role) error {
+	if len(emails) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.User{}).Where("email IN ?", emails).Update("role", role).Error
+}
+
 // Update updates a user
 func (r *userRepository) Update(user *model.User) error {
 	return r.db.Save(user).Error
 }
 
-// Delete deletes a user by ID
+// AdvanceTOTPStep records the time step of a used TOTP code.
+// It returns false if the same or a later step was already used.
+func (r *userRepository) AdvanceTOTPStep(id string, step int64) (bool, error) {
+	result := r.db.Model(&model.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// EmailExists reports whether an account uses the email, including deleted accounts awaiting purge
+func (r *userRepository) EmailExists(email string) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&model.User{}).Where("email = ?", email).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Delete soft-deletes a user and their todos by ID
 func (r *userRepository) Delete(id string) error {
-	return r.db.Where("id = ?", id).Delete(&model.User{}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", id).Delete(&model.Todo{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.User{}).Error
+	})
+}
+
+// userOwnedModels are removed together with a user when the user is purged.
+// Rows that reference todos come before the todos themselves.
+var userOwnedModels = []interface{}{
+	&model.TodoItem{},
+	&model.Reminder{},
+	&model.Todo{},
+	&model.Tag{},
+	&model.Project{},
+	&model.RefreshToken{},
+	&model.Session{},
+	&model.OneTimeToken{},
+	&model.MFARecoveryCode{},
+	&model.PersonalAccessToken{},
+	&model.ExternalIdentity{},
+	&model.WebAuthnCredential{},
+	&model.UserTokenVersion{},
+}
+
+// PurgeDeleted permanently removes users soft-deleted before the given time
+// together with the data they own. It returns the number of purged users.
+func (r *userRepository) PurgeDeleted(deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		err := tx.Unscoped().Model(&model.User{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		// Tag assignments have no user_id of their own
+		if err := tx.Exec("DELETE FROM todo_tags WHERE todo_id IN (SELECT id FROM todos WHERE user_id IN ?)", ids).Error; err != nil {
+			return err
+		}
+
+		for _, owned := range userOwnedModels {
+			if err := tx.Unscoped().Where("user_id IN ?", ids).Delete(owned).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&model.User{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
 }
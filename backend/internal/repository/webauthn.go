@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebAuthnRepository defines the interface for WebAuthn challenge and credential data operations
+type WebAuthnRepository interface {
+	CreateChallenge(challenge *model.WebAuthnChallenge) error
+	ConsumeChallenge(challengeHash string, ceremony model.WebAuthnCeremony) (*model.WebAuthnChallenge, error)
+	DeleteExpiredChallenges(now time.Time) error
+	CreateCredential(credential *model.WebAuthnCredential) error
+	GetCredentialByCredentialID(credentialID string) (*model.WebAuthnCredential, error)
+	GetCredential(userID, id string) (*model.WebAuthnCredential, error)
+	ListCredentials(userID string) ([]model.WebAuthnCredential, error)
+	RenameCredential(userID, id, name string) (bool, error)
+	RecordUse(id string, signCount int64, usedAt time.Time) error
+	DeleteCredential(userID, id string) (bool, error)
+}
+
+// webauthnRepository implements WebAuthnRepository interface
+type webauthnRepository struct {
+	db *gorm.DB
+}
+
+// NewWebAuthnRepository creates a new WebAuthn repository
+func NewWebAuthnRepository(db *gorm.DB) WebAuthnRepository {
+	return &webauthnRepository{db: db}
+}
+
+// CreateChallenge stores the challenge of a pending ceremony
+func (r *webauthnRepository) CreateChallenge(challenge *model.WebAuthnChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+// ConsumeChallenge deletes and returns a challenge so it can only be answered once.
+// It returns gorm.ErrRecordNotFound if no challenge of the ceremony exists.
+func (r *webauthnRepository) ConsumeChallenge(challengeHash string, ceremony model.WebAuthnCeremony) (*model.WebAuthnChallenge, error) {
+	var challenges []model.WebAuthnChallenge
+	err := r.db.Clauses(clause.Returning{}).
+		Where("challenge_hash = ? AND ceremony = ?", challengeHash, ceremony).
+		Delete(&challenges).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(challenges) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &challenges[0], nil
+}
+
+// DeleteExpiredChallenges removes challenges of ceremonies that were never finished
+func (r *webauthnRepository) DeleteExpiredChallenges(now time.Time) error {
+	return r.db.Where("expires_at < ?", now).Delete(&model.WebAuthnChallenge{}).Error
+}
+
+// CreateCredential stores a newly registered credential
+func (r *webauthnRepository) CreateCredential(credential *model.WebAuthnCredential) error {
+	return r.db.Create(credential).Error
+}
+
+// GetCredentialByCredentialID retrieves a credential by the ID the authenticator assigned to it
+func (r *webauthnRepository) GetCredentialByCredentialID(credentialID string) (*model.WebAuthnCredential, error) {
+	var credential model.WebAuthnCredential
+	err := r.db.Where("credential_id = ?", credentialID).First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// GetCredential retrieves a credential that belongs to a specific user
+func (r *webauthnRepository) GetCredential(userID, id string) (*model.WebAuthnCredential, error) {
+	var credential model.WebAuthnCredential
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// ListCredentials retrieves the credentials of a user, oldest first
+func (r *webauthnRepository) ListCredentials(userID string) ([]model.WebAuthnCredential, error) {
+	var credentials []model.WebAuthnCredential
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&credentials).Error
+	if err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// RenameCredential changes the name of a credential that belongs to a specific user.
+// It returns false if no such credential exists.
+func (r *webauthnRepository) RenameCredential(userID, id, name string) (bool, error) {
+	result := r.db.Model(&model.WebAuthnCredential{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("name", name)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RecordUse stores the signature counter and time of a successful login
+func (r *webauthnRepository) RecordUse(id string, signCount int64, usedAt time.Time) error {
+	return r.db.Model(&model.WebAuthnCredential{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"sign_count": signCount, "last_used_at": usedAt}).Error
+}
+
+// DeleteCredential deletes a credential that belongs to a specific user.
+// It returns false if no such credential exists.
+func (r *webauthnRepository) DeleteCredential(userID, id string) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.WebAuthnCredential{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
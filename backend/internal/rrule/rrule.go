@@ -0,0 +1,483 @@
+// Package rrule parses and expands the subset of RFC 5545 recurrence rules used by
+// recurring todos: DAILY, WEEKLY, MONTHLY and YEARLY frequencies with INTERVAL, COUNT,
+// UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ rule part
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxEmptyPeriods bounds how many periods in a row may pass without a candidate, so
+// rules that can never match (such as February 30th) stop instead of looping forever.
+// It is well above the longest gap of a valid rule, eight years of daily periods
+// for February 29th.
+const maxEmptyPeriods = 10000
+
+// untilLayout is the UTC date-time form used for UNTIL
+const untilLayout = "20060102T150405Z"
+
+var weekdayNames = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayPattern = regexp.MustCompile(`^([+-]?\d{1,2})?(SU|MO|TU|WE|TH|FR|SA)$`)
+
+// WeekdayNum is a BYDAY entry. A non-zero ordinal selects the nth weekday of the month,
+// counting from the end when negative (-1FR is the last Friday).
+type WeekdayNum struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH".
+// An "RRULE:" prefix is accepted.
+func Parse(value string) (*Rule, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimPrefix(value, "RRULE:")
+	if value == "" {
+		return nil, errors.New("rrule: empty rule")
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("rrule: malformed rule part %q", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("rrule: duplicate rule part %s", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch Frequency(val) {
+			case Daily, Weekly, Monthly, Yearly:
+				rule.Freq = Frequency(val)
+			default:
+				err = fmt.Errorf("rrule: unsupported frequency %s", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(key, val)
+		case "COUNT":
+			rule.Count, err = parsePositive(key, val)
+		case "UNTIL":
+			rule.Until, err = parseUntil(val)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseIntList(key, val, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(key, val, 1, 12)
+			for _, m := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(m))
+			}
+			slices.Sort(rule.ByMonth)
+		case "WKST":
+			weekday, known := weekdayNames[val]
+			if !known {
+				err = fmt.Errorf("rrule: invalid WKST %s", val)
+			}
+			rule.WeekStart = weekday
+		default:
+			err = fmt.Errorf("rrule: unsupported rule part %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// validate rejects combinations RFC 5545 forbids or this package does not expand
+func (r *Rule) validate() error {
+	if r.Freq == "" {
+		return errors.New("rrule: FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return errors.New("rrule: COUNT and UNTIL cannot both be set")
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return errors.New("rrule: BYMONTHDAY cannot be used with WEEKLY")
+	}
+	if r.Freq == Yearly && len(r.ByDay) > 0 && len(r.ByMonth) == 0 {
+		return errors.New("rrule: BYDAY with YEARLY requires BYMONTH")
+	}
+	for _, day := range r.ByDay {
+		if day.Ordinal != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return errors.New("rrule: BYDAY ordinals require MONTHLY or YEARLY")
+		}
+	}
+	return nil
+}
+
+// String returns the rule in canonical form
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = weekdayName(d.Weekday)
+			if d.Ordinal != 0 {
+				days[i] = strconv.Itoa(d.Ordinal) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayName(r.WeekStart))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence after the given time of the series that starts at dtstart.
+// It returns false once the series has ended.
+func (r *Rule) Next(dtstart, after time.Time) (time.Time, bool) {
+	occurrences := r.Occurrences(dtstart, after, 1)
+	if len(occurrences) == 0 {
+		return time.Time{}, false
+	}
+	return occurrences[0], true
+}
+
+// Occurrences returns up to n occurrences after the given time of the series that starts at dtstart
+func (r *Rule) Occurrences(dtstart, after time.Time, n int) []time.Time {
+	if n <= 0 {
+		return []time.Time{}
+	}
+	occurrences := make([]time.Time, 0, n)
+	r.iterate(dtstart, after, func(t time.Time) bool {
+		if t.After(after) {
+			occurrences = append(occurrences, t)
+		}
+		return len(occurrences) < n
+	})
+	return occurrences
+}
+
+// iterate calls yield with every occurrence in order until it returns false or the
+// series ends. DTSTART is always the first occurrence and counts towards COUNT.
+// Without COUNT, iteration starts near the period containing after instead of
+// replaying the series from DTSTART; occurrences before after may still be yielded.
+func (r *Rule) iterate(dtstart, after time.Time, yield func(time.Time) bool) {
+	emitted := 0
+	emit := func(t time.Time) bool {
+		if !r.Until.IsZero() && t.After(r.Until) {
+			return false
+		}
+		emitted++
+		if !yield(t) {
+			return false
+		}
+		return r.Count == 0 || emitted < r.Count
+	}
+
+	first := 0
+	if r.Count == 0 {
+		first = r.periodBefore(dtstart, after)
+	}
+	if first == 0 && !emit(dtstart) {
+		return
+	}
+	for period, empty := first, 0; empty < maxEmptyPeriods; period++ {
+		candidates := r.candidates(dtstart, period)
+		if len(candidates) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+		for _, t := range candidates {
+			if !t.After(dtstart) {
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// periodBefore returns a period that ends before the given time, as close to it as
+// possible, or 0 when the time is within the first two periods of the series.
+// One period of slack covers time zone and daylight saving differences.
+func (r *Rule) periodBefore(dtstart, t time.Time) int {
+	if !t.After(dtstart) {
+		return 0
+	}
+	t = t.In(dtstart.Location())
+	year, month, day := dtstart.Date()
+	startDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	days := int((date.Unix() - startDate.Unix()) / (24 * 60 * 60))
+
+	var elapsed int
+	switch r.Freq {
+	case Daily:
+		elapsed = days
+	case Weekly:
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		elapsed = (days + offset) / 7
+	case Monthly:
+		elapsed = (t.Year()-year)*12 + int(t.Month()) - int(month)
+	case Yearly:
+		elapsed = t.Year() - year
+	}
+	return max(elapsed/r.Interval-1, 0)
+}
+
+// candidates returns the occurrences within the nth period of the series in order
+func (r *Rule) candidates(dtstart time.Time, period int) []time.Time {
+	year, month, day := dtstart.Date()
+	hour, minute, second := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, minute, second, dtstart.Nanosecond(), loc)
+	}
+
+	var candidates []time.Time
+	switch r.Freq {
+	case Daily:
+		t := at(year, month, day+period*r.Interval)
+		if r.matchesMonth(t.Month()) && r.matchesMonthDay(t) && r.matchesWeekday(t) {
+			candidates = append(candidates, t)
+		}
+	case Weekly:
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := day - offset + 7*period*r.Interval
+		for i := 0; i < 7; i++ {
+			t := at(year, month, weekStart+i)
+			if r.matchesMonth(t.Month()) && r.matchesWeeklyDay(t, dtstart.Weekday()) {
+				candidates = append(candidates, t)
+			}
+		}
+	case Monthly:
+		first := time.Date(year, month+time.Month(period*r.Interval), 1, 0, 0, 0, 0, loc)
+		if r.matchesMonth(first.Month()) {
+			candidates = r.expandMonth(first.Year(), first.Month(), day, at)
+		}
+	case Yearly:
+		y := year + period*r.Interval
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{month}
+			// BYMONTHDAY alone repeats in every month of the year
+			if len(r.ByMonthDay) > 0 {
+				months = []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			}
+		}
+		for _, m := range months {
+			candidates = append(candidates, r.expandMonth(y, m, day, at)...)
+		}
+	}
+	return candidates
+}
+
+// expandMonth returns the matching days of a month. Without BYDAY or BYMONTHDAY the
+// day of DTSTART is used, and months that lack that day are skipped.
+func (r *Rule) expandMonth(year int, month time.Month, startDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	length := daysIn(year, month)
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		if startDay > length {
+			return nil
+		}
+		return []time.Time{at(year, month, startDay)}
+	}
+
+	var days []time.Time
+	for d := 1; d <= length; d++ {
+		t := at(year, month, d)
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(t) {
+			continue
+		}
+		if len(r.ByDay) > 0 && !r.matchesMonthWeekday(d, length, t.Weekday()) {
+			continue
+		}
+		days = append(days, t)
+	}
+	return days
+}
+
+func (r *Rule) matchesMonth(month time.Month) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, month)
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	length := daysIn(t.Year(), t.Month())
+	for _, md := range r.ByMonthDay {
+		if md == t.Day() || (md < 0 && length+md+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, day := range r.ByDay {
+		if day.Weekday == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeeklyDay uses the weekday of DTSTART when BYDAY is not set
+func (r *Rule) matchesWeeklyDay(t time.Time, startWeekday time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return t.Weekday() == startWeekday
+	}
+	return r.matchesWeekday(t)
+}
+
+// matchesMonthWeekday checks BYDAY entries, with ordinals counted within the month
+func (r *Rule) matchesMonthWeekday(day, length int, weekday time.Weekday) bool {
+	for _, entry := range r.ByDay {
+		if entry.Weekday != weekday {
+			continue
+		}
+		switch {
+		case entry.Ordinal == 0:
+			return true
+		case entry.Ordinal > 0 && (day-1)/7+1 == entry.Ordinal:
+			return true
+		case entry.Ordinal < 0 && (length-day)/7+1 == -entry.Ordinal:
+			return true
+		}
+	}
+	return false
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func weekdayName(weekday time.Weekday) string {
+	for name, day := range weekdayNames {
+		if day == weekday {
+			return name
+		}
+	}
+	return ""
+}
+
+func parsePositive(key, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("rrule: %s must be a positive integer", key)
+	}
+	return n, nil
+}
+
+// parseUntil accepts UTC and floating date-times as well as dates. A date includes the whole day.
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{untilLayout, "20060102T150405"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("rrule: invalid UNTIL %s", value)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		match := weekdayPattern.FindStringSubmatch(item)
+		if match == nil {
+			return nil, fmt.Errorf("rrule: invalid BYDAY %s", item)
+		}
+		day := WeekdayNum{Weekday: weekdayNames[match[2]]}
+		if match[1] != "" {
+			ordinal, _ := strconv.Atoi(match[1])
+			if ordinal == 0 || ordinal < -5 || ordinal > 5 {
+				return nil, fmt.Errorf("rrule: invalid BYDAY ordinal %s", item)
+			}
+			day.Ordinal = ordinal
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func parseIntList(key, value string, min, max int) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, fmt.Errorf("rrule: invalid %s value %s", key, item)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
@@ -0,0 +1,303 @@
+package rrule
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"daily", "FREQ=DAILY", "FREQ=DAILY"},
+		{"prefix and case", " rrule:freq=weekly;byday=mo,th ", "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{"interval of one is dropped", "FREQ=DAILY;INTERVAL=1", "FREQ=DAILY"},
+		{"canonical order", "BYDAY=-1FR;INTERVAL=2;FREQ=MONTHLY", "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR"},
+		{"count", "FREQ=WEEKLY;COUNT=3", "FREQ=WEEKLY;COUNT=3"},
+		{"until date", "FREQ=DAILY;UNTIL=20240131", "FREQ=DAILY;UNTIL=20240131T235959Z"},
+		{"until date-time", "FREQ=DAILY;UNTIL=20240131T120000Z", "FREQ=DAILY;UNTIL=20240131T120000Z"},
+		{"months are sorted", "FREQ=YEARLY;BYMONTH=12,3;BYDAY=1SU", "FREQ=YEARLY;BYMONTH=3,12;BYDAY=1SU"},
+		{"wkst", "FREQ=WEEKLY;WKST=SU", "FREQ=WEEKLY;WKST=SU"},
+		{"monday wkst is dropped", "FREQ=WEEKLY;WKST=MO", "FREQ=WEEKLY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.value)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.value, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"empty", ""},
+		{"no freq", "INTERVAL=2"},
+		{"unsupported freq", "FREQ=HOURLY"},
+		{"malformed part", "FREQ=DAILY;COUNT"},
+		{"empty value", "FREQ=DAILY;COUNT="},
+		{"duplicate part", "FREQ=DAILY;FREQ=WEEKLY"},
+		{"unsupported part", "FREQ=DAILY;BYHOUR=9"},
+		{"zero interval", "FREQ=DAILY;INTERVAL=0"},
+		{"negative count", "FREQ=DAILY;COUNT=-1"},
+		{"count and until", "FREQ=DAILY;COUNT=2;UNTIL=20240101"},
+		{"invalid until", "FREQ=DAILY;UNTIL=tomorrow"},
+		{"invalid weekday", "FREQ=WEEKLY;BYDAY=XX"},
+		{"zero ordinal", "FREQ=MONTHLY;BYDAY=0MO"},
+		{"ordinal out of range", "FREQ=MONTHLY;BYDAY=6MO"},
+		{"ordinal with weekly", "FREQ=WEEKLY;BYDAY=1MO"},
+		{"zero month day", "FREQ=MONTHLY;BYMONTHDAY=0"},
+		{"month day out of range", "FREQ=MONTHLY;BYMONTHDAY=32"},
+		{"month out of range", "FREQ=YEARLY;BYMONTH=13"},
+		{"month day with weekly", "FREQ=WEEKLY;BYMONTHDAY=1"},
+		{"yearly byday without bymonth", "FREQ=YEARLY;BYDAY=MO"},
+		{"invalid wkst", "FREQ=WEEKLY;WKST=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.value); err == nil {
+				t.Errorf("Parse(%q) error = nil, want an error", tt.value)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		n       int
+		want    []time.Time
+	}{
+		{
+			name:    "daily",
+			rule:    "FREQ=DAILY",
+			dtstart: date(2024, 1, 30, 9, 0),
+			n:       3,
+			want:    []time.Time{date(2024, 1, 31, 9, 0), date(2024, 2, 1, 9, 0), date(2024, 2, 2, 9, 0)},
+		},
+		{
+			name:    "every other week on monday and thursday",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			dtstart: date(2024, 1, 1, 8, 0),
+			n:       4,
+			want:    []time.Time{date(2024, 1, 4, 8, 0), date(2024, 1, 15, 8, 0), date(2024, 1, 18, 8, 0), date(2024, 1, 29, 8, 0)},
+		},
+		{
+			name:    "weekly on the weekday of dtstart",
+			rule:    "FREQ=WEEKLY",
+			dtstart: date(2024, 1, 6, 10, 0),
+			n:       2,
+			want:    []time.Time{date(2024, 1, 13, 10, 0), date(2024, 1, 20, 10, 0)},
+		},
+		{
+			name:    "monthly skips months without the day",
+			rule:    "FREQ=MONTHLY",
+			dtstart: date(2024, 1, 31, 12, 0),
+			n:       3,
+			want:    []time.Time{date(2024, 3, 31, 12, 0), date(2024, 5, 31, 12, 0), date(2024, 7, 31, 12, 0)},
+		},
+		{
+			name:    "last friday of the month",
+			rule:    "FREQ=MONTHLY;BYDAY=-1FR",
+			dtstart: date(2024, 1, 26, 17, 0),
+			n:       3,
+			want:    []time.Time{date(2024, 2, 23, 17, 0), date(2024, 3, 29, 17, 0), date(2024, 4, 26, 17, 0)},
+		},
+		{
+			name:    "last day of the month",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1",
+			dtstart: date(2024, 1, 31, 0, 0),
+			n:       3,
+			want:    []time.Time{date(2024, 2, 29, 0, 0), date(2024, 3, 31, 0, 0), date(2024, 4, 30, 0, 0)},
+		},
+		{
+			name:    "second sunday of march and november",
+			rule:    "FREQ=YEARLY;BYMONTH=3,11;BYDAY=2SU",
+			dtstart: date(2024, 3, 10, 2, 0),
+			n:       3,
+			want:    []time.Time{date(2024, 11, 10, 2, 0), date(2025, 3, 9, 2, 0), date(2025, 11, 9, 2, 0)},
+		},
+		{
+			name:    "leap day",
+			rule:    "FREQ=YEARLY",
+			dtstart: date(2024, 2, 29, 0, 0),
+			n:       2,
+			want:    []time.Time{date(2028, 2, 29, 0, 0), date(2032, 2, 29, 0, 0)},
+		},
+		{
+			name:    "count includes dtstart",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: date(2024, 1, 1, 9, 0),
+			n:       5,
+			want:    []time.Time{date(2024, 1, 2, 9, 0), date(2024, 1, 3, 9, 0)},
+		},
+		{
+			name:    "until is inclusive",
+			rule:    "FREQ=DAILY;UNTIL=20240103T090000Z",
+			dtstart: date(2024, 1, 1, 9, 0),
+			n:       5,
+			want:    []time.Time{date(2024, 1, 2, 9, 0), date(2024, 1, 3, 9, 0)},
+		},
+		{
+			name:    "impossible rule ends",
+			rule:    "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			dtstart: date(2024, 1, 1, 0, 0),
+			n:       1,
+			want:    []time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+			got := rule.Occurrences(tt.dtstart, tt.dtstart, tt.n)
+			if !slices.EqualFunc(got, tt.want, time.Time.Equal) {
+				t.Errorf("Occurrences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextAfterLongSeries(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		after   time.Time
+		want    time.Time
+	}{
+		{
+			name:    "daily beyond ten thousand periods",
+			rule:    "FREQ=DAILY",
+			dtstart: date(2000, 1, 1, 9, 0),
+			after:   date(2040, 6, 15, 9, 0),
+			want:    date(2040, 6, 16, 9, 0),
+		},
+		{
+			name:    "daily on leap days",
+			rule:    "FREQ=DAILY;BYMONTH=2;BYMONTHDAY=29",
+			dtstart: date(2096, 2, 29, 0, 0),
+			after:   date(2096, 3, 1, 0, 0),
+			want:    date(2104, 2, 29, 0, 0),
+		},
+		{
+			name:    "weekly with sunday week start",
+			rule:    "FREQ=WEEKLY;INTERVAL=3;BYDAY=SU,SA;WKST=SU",
+			dtstart: date(2024, 1, 6, 7, 30),
+			after:   date(2224, 4, 25, 12, 0),
+			want:    date(2224, 5, 1, 7, 30),
+		},
+		{
+			name:    "after in another time zone",
+			rule:    "FREQ=DAILY",
+			dtstart: date(2024, 1, 1, 23, 0),
+			after:   time.Date(2024, 6, 2, 7, 0, 0, 0, time.FixedZone("UTC+9", 9*60*60)),
+			want:    date(2024, 6, 1, 23, 0),
+		},
+		{
+			name:    "after before dtstart",
+			rule:    "FREQ=MONTHLY",
+			dtstart: date(2024, 5, 10, 0, 0),
+			after:   date(2020, 1, 1, 0, 0),
+			want:    date(2024, 5, 10, 0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+			got, ok := rule.Next(tt.dtstart, tt.after)
+			if !ok || !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, %v, want %v, true", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+// TestNextMatchesReplay checks that starting near the given time yields the same
+// occurrences as replaying the series from DTSTART
+func TestNextMatchesReplay(t *testing.T) {
+	rules := []string{
+		"FREQ=DAILY;INTERVAL=3",
+		"FREQ=DAILY;BYDAY=MO,WE,FR",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SU",
+		"FREQ=WEEKLY;INTERVAL=5;WKST=SU",
+		"FREQ=MONTHLY;INTERVAL=4;BYMONTHDAY=1,-1",
+		"FREQ=MONTHLY;BYDAY=2WE",
+		"FREQ=YEARLY;INTERVAL=2;BYMONTHDAY=15",
+		"FREQ=YEARLY;BYMONTH=6;BYDAY=-1SA",
+	}
+	dtstart := time.Date(2023, 3, 26, 1, 30, 0, 0, time.FixedZone("CET", 60*60))
+
+	for _, value := range rules {
+		t.Run(value, func(t *testing.T) {
+			rule, err := Parse(value)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", value, err)
+			}
+			series := rule.Occurrences(dtstart, dtstart, 200)
+			for i, after := range series[:len(series)-1] {
+				got, ok := rule.Next(dtstart, after)
+				if !ok || !got.Equal(series[i+1]) {
+					t.Fatalf("Next(%v) = %v, %v, want %v", after, got, ok, series[i+1])
+				}
+				// A time between two occurrences also lands on the later one
+				got, ok = rule.Next(dtstart, after.Add(time.Minute))
+				if !ok || !got.Equal(series[i+1]) {
+					t.Fatalf("Next(%v) = %v, %v, want %v", after.Add(time.Minute), got, ok, series[i+1])
+				}
+			}
+		})
+	}
+}
+
+func TestNextEnded(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		after   time.Time
+	}{
+		{"count reached", "FREQ=WEEKLY;COUNT=2", date(2024, 1, 1, 0, 0), date(2024, 1, 8, 0, 0)},
+		{"single occurrence", "FREQ=DAILY;COUNT=1", date(2024, 1, 1, 0, 0), date(2024, 1, 1, 0, 0)},
+		{"past until", "FREQ=DAILY;UNTIL=20240110", date(2024, 1, 1, 0, 0), date(2030, 1, 1, 0, 0)},
+		{"until before dtstart", "FREQ=DAILY;UNTIL=20231231", date(2024, 1, 1, 0, 0), date(2024, 1, 1, 0, 0)},
+		{"february 30th", "FREQ=MONTHLY;BYMONTH=2;BYMONTHDAY=30", date(2024, 1, 30, 0, 0), date(2024, 1, 30, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+			if got, ok := rule.Next(tt.dtstart, tt.after); ok {
+				t.Errorf("Next() = %v, true, want the series to have ended", got)
+			}
+		})
+	}
+}
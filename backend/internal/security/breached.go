@@ -0,0 +1,107 @@
+package security
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BreachedPasswords reports whether a password appears in a list of breached passwords
+type BreachedPasswords interface {
+	Contains(password string) (bool, error)
+}
+
+// LoadBreachedPasswords opens an offline breached-password list in the Have I
+// Been Pwned format, where each line is an uppercase SHA-1 hash optionally
+// followed by ":count". path may be a single file, which is loaded into
+// memory, or a directory of range files named after the first five hex
+// characters of the hash (for example "5BAA6.txt") containing the remaining
+// 35 characters, which are read on demand so that full dumps can be used.
+func LoadBreachedPasswords(path string) (BreachedPasswords, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		return &breachedRangeDir{dir: path}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hashes := make(map[[sha1.Size]byte]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var hash [sha1.Size]byte
+		line := hashField(scanner.Text())
+		if len(line) != hex.EncodedLen(sha1.Size) {
+			continue
+		}
+		if _, err := hex.Decode(hash[:], []byte(line)); err != nil {
+			continue
+		}
+		hashes[hash] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return breachedHashSet(hashes), nil
+}
+
+// breachedHashSet is a breached-password list held in memory
+type breachedHashSet map[[sha1.Size]byte]struct{}
+
+// Contains implements BreachedPasswords
+func (s breachedHashSet) Contains(password string) (bool, error) {
+	_, ok := s[sha1.Sum([]byte(password))]
+	return ok, nil
+}
+
+// breachedRangeDir is a breached-password list split into range files
+type breachedRangeDir struct {
+	dir string
+}
+
+// Contains implements BreachedPasswords
+func (d *breachedRangeDir) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	file, err := os.Open(filepath.Join(d.dir, prefix+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		file, err = os.Open(filepath.Join(d.dir, prefix))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := hashField(scanner.Text())
+		// Range files may contain either the suffix or the full hash
+		if line == suffix || line == hash {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// hashField returns the normalised hash of a list line, dropping an optional ":count"
+func hashField(line string) string {
+	line, _, _ = strings.Cut(strings.TrimSpace(line), ":")
+	return strings.ToUpper(line)
+}
@@ -0,0 +1,200 @@
+package security
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// commonPasswords are frequently used passwords and password fragments, most common first
+var commonPasswords = []string{
+	"password", "123456", "qwerty", "letmein", "welcome", "admin", "iloveyou",
+	"monkey", "dragon", "football", "baseball", "master", "sunshine", "princess",
+	"shadow", "superman", "batman", "trustno", "hello", "freedom", "whatever",
+	"secret", "login", "passw", "starwars", "access", "flower", "hunter",
+	"soccer", "hockey", "killer", "computer", "internet", "changeme", "default",
+	"guest", "user", "root", "test", "pass", "summer", "winter", "spring",
+	"autumn", "love", "lovely", "angel", "michael", "jennifer", "jordan",
+	"charlie", "thomas", "daniel", "andrew", "jessica", "ashley", "robert",
+	"matthew", "pepper", "ginger", "cookie", "cheese", "banana", "orange",
+	"purple", "silver", "golden", "diamond", "tigger", "buster", "maggie",
+	"money", "family", "friends", "forever", "happy", "lucky", "magic",
+	"todo", "todos", "zaq", "qazwsx", "asdf", "zxcv", "abc", "abcd",
+}
+
+// keyboardRows are adjacent key sequences on a QWERTY keyboard
+var keyboardRows = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+}
+
+// leetSubstitutions maps common character substitutions back to letters
+var leetSubstitutions = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b',
+	'@': 'a', '$': 's', '!': 'i', '+': 't',
+}
+
+// PasswordStrength estimates how hard a password is to guess on a zxcvbn-style
+// scale from 0 (too guessable) to 4 (very unguessable). The password is split
+// into the cheapest sequence of recognisable patterns (common passwords, user
+// inputs, keyboard rows, sequences, repeats) and brute-forced characters, and
+// the product of their guess counts is mapped to the score.
+func PasswordStrength(password string, userInputs ...string) int {
+	guesses := EstimateGuesses(password, userInputs...)
+	switch {
+	case guesses < 1e3:
+		return 0
+	case guesses < 1e6:
+		return 1
+	case guesses < 1e8:
+		return 2
+	case guesses < 1e10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// EstimateGuesses estimates the number of guesses an attacker needs to find a password
+func EstimateGuesses(password string, userInputs ...string) float64 {
+	runes := []rune(password)
+	n := len(runes)
+	if n == 0 {
+		return 1
+	}
+
+	lower := []rune(strings.ToLower(password))
+	unleet := make([]rune, n)
+	for i, r := range lower {
+		if sub, ok := leetSubstitutions[r]; ok {
+			unleet[i] = sub
+		} else {
+			unleet[i] = r
+		}
+	}
+
+	words := make([]string, 0, len(commonPasswords)+len(userInputs))
+	words = append(words, commonPasswords...)
+	for _, input := range userInputs {
+		for _, token := range strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len([]rune(token)) >= 3 {
+				words = append(words, token)
+			}
+		}
+	}
+
+	// best[i] is the lowest log10 guess count for the first i characters
+	best := make([]float64, n+1)
+	for i := 1; i <= n; i++ {
+		best[i] = math.Inf(1)
+	}
+
+	for end := 1; end <= n; end++ {
+		// A single brute-forced character
+		if g := best[end-1] + math.Log10(characterCardinality(runes[end-1])); g < best[end] {
+			best[end] = g
+		}
+
+		for start := 0; start <= end-3; start++ {
+			if cost, ok := patternGuesses(runes[start:end], lower[start:end], unleet[start:end], words); ok {
+				if g := best[start] + math.Log10(cost); g < best[end] {
+					best[end] = g
+				}
+			}
+		}
+	}
+
+	return math.Pow(10, best[n])
+}
+
+// patternGuesses returns the guess count of a segment if it matches a known pattern
+func patternGuesses(original, lower, unleet []rune, words []string) (float64, bool) {
+	length := float64(len(lower))
+	bestCost := math.Inf(1)
+
+	// Dictionary words, possibly capitalised or with substitutions
+	for rank, word := range words {
+		if string(unleet) != word && string(lower) != word {
+			continue
+		}
+		cost := float64(rank + 1)
+		if string(lower) != word {
+			cost *= 4
+		}
+		if string(original) != string(lower) {
+			cost *= 2
+		}
+		bestCost = math.Min(bestCost, math.Max(cost, 10))
+		break
+	}
+
+	// Repeated characters such as "aaaa"
+	if isRepeat(lower) {
+		bestCost = math.Min(bestCost, characterCardinality(original[0])*length)
+	}
+
+	// Alphabetic or numeric sequences such as "abcd" or "9876"
+	if isSequence(lower) {
+		bestCost = math.Min(bestCost, 4*length)
+	}
+
+	// Runs along a keyboard row such as "qwerty"
+	if len(lower) >= 4 && isKeyboardRun(string(lower)) {
+		bestCost = math.Min(bestCost, 10*length)
+	}
+
+	return bestCost, !math.IsInf(bestCost, 1)
+}
+
+// characterCardinality returns the size of the character class of r
+func characterCardinality(r rune) float64 {
+	switch {
+	case r >= '0' && r <= '9':
+		return 10
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		return 26
+	case r < unicode.MaxASCII:
+		return 33
+	default:
+		return 100
+	}
+}
+
+func isRepeat(s []rune) bool {
+	for _, r := range s[1:] {
+		if r != s[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func isSequence(s []rune) bool {
+	delta := s[1] - s[0]
+	if delta != 1 && delta != -1 {
+		return false
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i]-s[i-1] != delta {
+			return false
+		}
+	}
+	return true
+}
+
+func isKeyboardRun(s string) bool {
+	reversed := []rune(s)
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	for _, row := range keyboardRows {
+		if strings.Contains(row, s) || strings.Contains(row, string(reversed)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+	"gorm.io/gorm"
+)
+
+// AccountService defines the interface for self-service account operations
+type AccountService interface {
+	UpdateProfile(userID string, req *model.UpdateProfileRequest) (*model.User, error)
+	ChangePassword(userID, sessionID string, req *model.ChangePasswordRequest, client model.ClientInfo) (*model.LoginResponse, error)
+	DeleteAccount(userID, sessionID string, req *model.DeleteAccountRequest, clientIP string) error
+	PurgeDeletedAccounts() error
+	StartPurge()
+}
+
+// accountService implements AccountService interface
+type accountService struct {
+	userRepo       repository.UserRepository
+	sessionRepo    repository.SessionRepository
+	authService    AuthService
+	loginThrottler LoginThrottler
+	passwordHasher security.PasswordHasher
+	passwordPolicy PasswordPolicy
+	config         *config.Config
+}
+
+// NewAccountService creates a new account service
+func NewAccountService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, authService AuthService, loginThrottler LoginThrottler, passwordHasher security.PasswordHasher, passwordPolicy PasswordPolicy, config *config.Config) AccountService {
+	return &accountService{
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		authService:    authService,
+		loginThrottler: loginThrottler,
+		passwordHasher: passwordHasher,
+		passwordPolicy: passwordPolicy,
+		config:         config,
+	}
+}
+
+// UpdateProfile updates the editable profile fields of a user
+func (s *accountService) UpdateProfile(userID string, req *model.UpdateProfileRequest) (*model.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	user.Name = req.Name
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ChangePassword sets a new password after checking the current one, or sets the first
+// password of an account without one. Every existing token is revoked and a new token
+// pair is returned for the caller.
+func (s *accountService) ChangePassword(userID, sessionID string, req *model.ChangePasswordRequest, client model.ClientInfo) (*model.LoginResponse, error) {
+	user, err := s.confirmIdentity(userID, sessionID, req.CurrentPassword, client.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.passwordPolicy.Validate(req.NewPassword, user.Email, user.Name); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.RevokeAllTokens(user.ID); err != nil {
+		return nil, err
+	}
+
+	return s.authService.IssueLoginResponse(user, client)
+}
+
+// DeleteAccount soft-deletes a user and their todos after checking the password.
+// The account is purged permanently once the grace period has passed.
+func (s *accountService) DeleteAccount(userID, sessionID string, req *model.DeleteAccountRequest, clientIP string) error {
+	user, err := s.confirmIdentity(userID, sessionID, req.Password, clientIP)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authService.RevokeAllTokens(user.ID); err != nil {
+		return err
+	}
+
+	return s.userRepo.Delete(user.ID)
+}
+
+// PurgeDeletedAccounts permanently removes accounts whose grace period has passed
+func (s *accountService) PurgeDeletedAccounts() error {
+	deletedBefore := time.Now().AddDate(0, 0, -s.config.Auth.AccountPurgeDays)
+	purged, err := s.userRepo.PurgeDeleted(deletedBefore)
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		log.Printf("Purged %d deleted accounts", purged)
+	}
+	return nil
+}
+
+// StartPurge purges deleted accounts in the background every hour
+func (s *accountService) StartPurge() {
+	purge := func() {
+		if err := s.PurgeDeletedAccounts(); err != nil {
+			log.Printf("Failed to purge deleted accounts: %v", err)
+		}
+	}
+
+	go func() {
+		purge()
+
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purge()
+		}
+	}()
+}
+
+// confirmIdentity loads a user and checks their password, counting failures against the
+// login throttle so a stolen token cannot be used to guess it. Accounts without a password
+// must use a session that signed in within the last few minutes instead.
+func (s *accountService) confirmIdentity(userID, sessionID, password, clientIP string) (*model.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	if !user.HasPassword() {
+		if err := checkRecentLogin(s.sessionRepo, user.ID, sessionID, s.config.Auth.ReauthMinutes); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	if err := s.loginThrottler.Reserve(user.Email, clientIP); err != nil {
+		return nil, err
+	}
+
+	match, err := s.passwordHasher.Verify(user.Password, password)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, errors.New("invalid password")
+	}
+
+	if err := s.loginThrottler.RecordSuccess(user.Email, clientIP); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// checkRecentLogin rejects sessions that signed in longer ago than the re-authentication
+// window. Requests without a session, such as those of a trusted proxy, are rejected too.
+func checkRecentLogin(sessionRepo repository.SessionRepository, userID, sessionID string, reauthMinutes int) error {
+	if sessionID == "" {
+		return errors.New("recent login required")
+	}
+
+	session, err := sessionRepo.GetByID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("recent login required")
+		}
+		return err
+	}
+
+	window := time.Duration(reauthMinutes) * time.Minute
+	if session.UserID != userID || time.Since(session.CreatedAt) > window {
+		return errors.New("recent login required")
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+func (r *fakeUserRepo) Delete(id string) error {
+	delete(r.users, id)
+	return nil
+}
+
+func (s *fakeAuthService) RevokeAllTokens(userID string) error {
+	return nil
+}
+
+// fakeSessionRepo keeps sessions in memory. Methods the tests do not use panic.
+type fakeSessionRepo struct {
+	repository.SessionRepository
+	sessions map[string]*model.Session
+}
+
+func (r *fakeSessionRepo) GetByID(id string) (*model.Session, error) {
+	if session, ok := r.sessions[id]; ok {
+		return session, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestDeleteAccountWithoutPassword(t *testing.T) {
+	tests := []struct {
+		name      string
+		sessionID string
+		wantErr   string
+	}{
+		{"fresh session", "fresh", ""},
+		{"stale session", "stale", "recent login required"},
+		{"session of another user", "other", "recent login required"},
+		{"unknown session", "unknown", "recent login required"},
+		{"no session", "", "recent login required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &fakeUserRepo{users: map[string]*model.User{
+				"user-1": {ID: "user-1", Email: "alice@example.com"},
+			}}
+			sessionRepo := &fakeSessionRepo{sessions: map[string]*model.Session{
+				"fresh": {ID: "fresh", UserID: "user-1", CreatedAt: time.Now().Add(-time.Minute)},
+				"stale": {ID: "stale", UserID: "user-1", CreatedAt: time.Now().Add(-time.Hour)},
+				"other": {ID: "other", UserID: "user-2", CreatedAt: time.Now()},
+			}}
+			cfg := &config.Config{Auth: config.AuthConfig{ReauthMinutes: 10}}
+			service := NewAccountService(userRepo, sessionRepo, &fakeAuthService{}, nil, nil, nil, cfg)
+
+			err := service.DeleteAccount("user-1", tt.sessionID, &model.DeleteAccountRequest{}, "127.0.0.1")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("DeleteAccount() error = %v", err)
+				}
+				if _, exists := userRepo.users["user-1"]; exists {
+					t.Error("DeleteAccount() kept the user")
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("DeleteAccount() error = %v, want %q", err, tt.wantErr)
+			}
+			if _, exists := userRepo.users["user-1"]; !exists {
+				t.Error("DeleteAccount() deleted the user after an error")
+			}
+		})
+	}
+}
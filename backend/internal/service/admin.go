@@ -0,0 +1,186 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AdminService defines the interface for administrative operations.
+// Every state-changing or privacy-sensitive action is recorded in the audit log.
+type AdminService interface {
+	ListUsers(req *model.UserListRequest) (*model.UserListResponse, error)
+	GetUser(userID string) (*model.User, error)
+	DisableUser(actor model.AuditActor, userID string) (*model.User, error)
+	EnableUser(actor model.AuditActor, userID string) (*model.User, error)
+	GetUserTodos(actor model.AuditActor, userID string, req *model.TodoListRequest) (*model.TodoListResponse, error)
+	ListAuditLogs(req *model.AuditLogListRequest) (*model.AuditLogListResponse, error)
+}
+
+// adminService implements AdminService interface
+type adminService struct {
+	userRepo     repository.UserRepository
+	auditLogRepo repository.AuditLogRepository
+	authService  AuthService
+	todoService  TodoService
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(userRepo repository.UserRepository, auditLogRepo repository.AuditLogRepository, authService AuthService, todoService TodoService) AdminService {
+	return &adminService{
+		userRepo:     userRepo,
+		auditLogRepo: auditLogRepo,
+		authService:  authService,
+		todoService:  todoService,
+	}
+}
+
+// ListUsers retrieves users with pagination and filters
+func (s *adminService) ListUsers(req *model.UserListRequest) (*model.UserListResponse, error) {
+	// Set default values
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		req.Limit = 20
+	}
+
+	users, total, err := s.userRepo.List(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response format
+	userResponses := make([]model.UserResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = user.ToResponse()
+	}
+
+	response := &model.UserListResponse{
+		Data:       userResponses,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.Limit))),
+	}
+
+	return response, nil
+}
+
+// GetUser retrieves a user by ID
+func (s *adminService) GetUser(userID string) (*model.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// DisableUser blocks a user from logging in and revokes all of their tokens
+func (s *adminService) DisableUser(actor model.AuditActor, userID string) (*model.User, error) {
+	if actor.UserID == userID {
+		return nil, errors.New("cannot disable own account")
+	}
+
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsDisabled() {
+		now := time.Now()
+		user.DisabledAt = &now
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.authService.RevokeAllTokens(user.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.record(actor, model.AuditActionUserDisabled, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// EnableUser lets a disabled user log in again
+func (s *adminService) EnableUser(actor model.AuditActor, userID string) (*model.User, error) {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsDisabled() {
+		user.DisabledAt = nil
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.record(actor, model.AuditActionUserEnabled, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUserTodos retrieves the todos of any user
+func (s *adminService) GetUserTodos(actor model.AuditActor, userID string, req *model.TodoListRequest) (*model.TodoListResponse, error) {
+	if _, err := s.GetUser(userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.record(actor, model.AuditActionUserTodosRead, userID); err != nil {
+		return nil, err
+	}
+
+	return s.todoService.GetList(userID, req)
+}
+
+// ListAuditLogs retrieves audit log entries with pagination and filters
+func (s *adminService) ListAuditLogs(req *model.AuditLogListRequest) (*model.AuditLogListResponse, error) {
+	// Set default values
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		req.Limit = 50
+	}
+
+	entries, total, err := s.auditLogRepo.List(req)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.AuditLogListResponse{
+		Data:       entries,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.Limit))),
+	}
+
+	return response, nil
+}
+
+// record writes an audit log entry for an action on a user
+func (s *adminService) record(actor model.AuditActor, action, targetID string) error {
+	entry := &model.AuditLog{
+		ActorID:    actor.UserID,
+		Action:     action,
+		TargetType: "user",
+		TargetID:   targetID,
+		IPAddress:  actor.IPAddress,
+	}
+	return s.auditLogRepo.Create(entry)
+}
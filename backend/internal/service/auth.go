@@ -2,52 +2,101 @@ package service
 
 import (
 	"errors"
+	"log"
+	"net/url"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/mailer"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
 	"gorm.io/gorm"
 )
 
 // AuthService defines the interface for authentication operations
 type AuthService interface {
 	Register(req *model.UserRequest) (*model.User, error)
-	Login(req *model.LoginRequest) (*model.LoginResponse, error)
-	GenerateToken(userID string) (string, error)
+	Login(req *model.LoginRequest, client model.ClientInfo) (*model.LoginResponse, *model.MFAChallenge, error)
+	Refresh(refreshToken string) (*model.LoginResponse, error)
+	IssueLoginResponse(user *model.User, client model.ClientInfo) (*model.LoginResponse, error)
+	ContinueLogin(user *model.User, client model.ClientInfo, multiFactor bool) (*model.LoginResponse, *model.MFAChallenge, error)
+	GenerateToken(user *model.User, sessionID string) (string, error)
+	GenerateRefreshToken(userID, familyID string) (string, error)
+	Logout(tokenString, refreshToken string) error
+	RevokeAllTokens(userID string) error
+	ForgotPassword(email string) error
+	ResetPassword(token, password string) error
+	RequestMagicLink(email string) error
+	LoginWithMagicLink(token string, client model.ClientInfo) (*model.LoginResponse, *model.MFAChallenge, error)
+	SendVerificationEmail(userID string) error
+	VerifyEmail(token string) error
+	VerifyMFAToken(tokenString string) (*model.User, error)
 	ValidateToken(tokenString string) (*jwt.Token, error)
 	GetUserFromToken(tokenString string) (*model.User, error)
+	AuthenticateToken(tokenString string) (*model.User, *model.Session, error)
 }
 
+// JWT token types
+const (
+	tokenTypeAccess = "access"
+	tokenTypeMFA    = "mfa_pending"
+)
+
 // authService implements AuthService interface
 type authService struct {
-	userRepo repository.UserRepository
-	config   *config.Config
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	revocationStore  repository.TokenRevocationStore
+	oneTimeTokenRepo repository.OneTimeTokenRepository
+	patRepo          repository.PersonalAccessTokenRepository
+	sessionRepo      repository.SessionRepository
+	mailer           mailer.Mailer
+	loginThrottler   LoginThrottler
+	magicLinkLimiter RateLimiter
+	passwordHasher   security.PasswordHasher
+	passwordPolicy   PasswordPolicy
+	keyManager       KeyManager
+	config           *config.Config
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo repository.UserRepository, config *config.Config) AuthService {
+func NewAuthService(userRepo repository.UserRepository, refreshTokenRepo repository.RefreshTokenRepository, revocationStore repository.TokenRevocationStore, oneTimeTokenRepo repository.OneTimeTokenRepository, patRepo repository.PersonalAccessTokenRepository, sessionRepo repository.SessionRepository, mailer mailer.Mailer, loginThrottler LoginThrottler, magicLinkLimiter RateLimiter, passwordHasher security.PasswordHasher, passwordPolicy PasswordPolicy, keyManager KeyManager, config *config.Config) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		config:   config,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		revocationStore:  revocationStore,
+		oneTimeTokenRepo: oneTimeTokenRepo,
+		patRepo:          patRepo,
+		sessionRepo:      sessionRepo,
+		mailer:           mailer,
+		loginThrottler:   loginThrottler,
+		magicLinkLimiter: magicLinkLimiter,
+		passwordHasher:   passwordHasher,
+		passwordPolicy:   passwordPolicy,
+		keyManager:       keyManager,
+		config:           config,
 	}
 }
 
 // Register creates a new user account
 func (s *authService) Register(req *model.UserRequest) (*model.User, error) {
-	// Check if user already exists
-	existingUser, err := s.userRepo.GetByEmail(req.Email)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	// Check if user already exists. Deleted accounts keep their address until they are purged.
+	exists, err := s.userRepo.EmailExists(req.Email)
+	if err != nil {
 		return nil, err
 	}
-	if existingUser != nil {
+	if exists {
 		return nil, errors.New("user with this email already exists")
 	}
 
+	if err := s.passwordPolicy.Validate(req.Password, req.Email, req.Name); err != nil {
+		return nil, err
+	}
+
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, err := s.passwordHasher.Hash(req.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -56,89 +105,814 @@ func (s *authService) Register(req *model.UserRequest) (*model.User, error) {
 	user := &model.User{
 		Name:     req.Name,
 		Email:    req.Email,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, err
 	}
 
+	// The account exists at this point; the user can ask for a new email
+	if err := s.sendVerificationEmail(user); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
+	}
+
 	return user, nil
 }
 
-// Login authenticates a user and returns a token
-func (s *authService) Login(req *model.LoginRequest) (*model.LoginResponse, error) {
+// Login authenticates a user and returns a token.
+// Users with two-factor authentication enabled get an MFA challenge instead.
+func (s *authService) Login(req *model.LoginRequest, client model.ClientInfo) (*model.LoginResponse, *model.MFAChallenge, error) {
+	if err := s.loginThrottler.Reserve(req.Email, client.IPAddress); err != nil {
+		return nil, nil, err
+	}
+
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(req.Email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("invalid email or password")
+			return nil, nil, errors.New("invalid email or password")
 		}
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Check password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("invalid email or password")
+	match, err := s.passwordHasher.Verify(user.Password, req.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !match {
+		return nil, nil, errors.New("invalid email or password")
+	}
+
+	if user.IsDisabled() {
+		return nil, nil, errors.New("account disabled")
+	}
+
+	// Upgrade hashes made with an outdated algorithm or parameters while the password is at hand
+	if s.passwordHasher.NeedsRehash(user.Password) {
+		if err := s.rehashPassword(user, req.Password); err != nil {
+			log.Printf("Failed to rehash password of user %s: %v", user.ID, err)
+		}
+	}
+
+	// Failures are only cleared once every factor has been checked
+	if user.IsTOTPEnabled() {
+		if err := s.loginThrottler.Release(client.IPAddress); err != nil {
+			return nil, nil, err
+		}
+		challenge, err := s.generateMFAChallenge(user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, challenge, nil
+	}
+
+	if err := s.loginThrottler.RecordSuccess(req.Email, client.IPAddress); err != nil {
+		return nil, nil, err
+	}
+
+	response, err := s.IssueLoginResponse(user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair.
+// The presented refresh token is rotated; presenting an already rotated
+// token revokes every token in its family and ends its session.
+func (s *authService) Refresh(refreshToken string) (*model.LoginResponse, error) {
+	stored, err := s.refreshTokenRepo.GetByHash(security.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	if stored.RotatedAt != nil {
+		if err := s.revokeReusedFamily(stored); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("refresh token reuse detected")
+	}
+
+	if stored.IsExpired() {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// Another request may have rotated the token in the meantime
+	rotated, err := s.refreshTokenRepo.MarkRotated(stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		if err := s.revokeReusedFamily(stored); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("refresh token reuse detected")
+	}
+
+	user, err := s.userRepo.GetByID(stored.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+	if user.IsDisabled() {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// The refresh token family is the session; refresh tokens issued
+	// before sessions existed require a new login
+	session, err := s.sessionRepo.GetByID(stored.FamilyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, err
+	}
+	if session.RevokedAt != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Hour * time.Duration(s.config.JWT.RefreshHours))
+	if err := s.sessionRepo.Extend(session.ID, now, expiresAt); err != nil {
+		return nil, err
+	}
+
+	token, err := s.GenerateToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	newRefreshToken, err := s.GenerateRefreshToken(user.ID, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.LoginResponse{
+		Token:        token,
+		RefreshToken: newRefreshToken,
+		User:         user.ToResponse(),
+	}
+
+	return response, nil
+}
+
+// ContinueLogin finishes a login whose first factor has been verified by another method.
+// Users with two-factor authentication get an MFA challenge unless the method already
+// verified several factors, such as a passkey with user verification.
+func (s *authService) ContinueLogin(user *model.User, client model.ClientInfo, multiFactor bool) (*model.LoginResponse, *model.MFAChallenge, error) {
+	if user.IsDisabled() {
+		return nil, nil, errors.New("account disabled")
+	}
+
+	if user.IsTOTPEnabled() && !multiFactor {
+		challenge, err := s.generateMFAChallenge(user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, challenge, nil
+	}
+
+	response, err := s.IssueLoginResponse(user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, nil, nil
+}
+
+// IssueLoginResponse starts a new session and generates an access and refresh token pair for it
+func (s *authService) IssueLoginResponse(user *model.User, client model.ClientInfo) (*model.LoginResponse, error) {
+	if user.IsDisabled() {
+		return nil, errors.New("account disabled")
+	}
+
+	// Every login starts a new session, which is also the refresh token family
+	now := time.Now()
+	session := &model.Session{
+		UserID:     user.ID,
+		UserAgent:  truncate(client.UserAgent, 512),
+		IPAddress:  client.IPAddress,
+		ExpiresAt:  now.Add(time.Hour * time.Duration(s.config.JWT.RefreshHours)),
+		LastSeenAt: now,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
 	}
 
 	// Generate token
-	token, err := s.GenerateToken(user.ID)
+	token, err := s.GenerateToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := s.GenerateRefreshToken(user.ID, session.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	response := &model.LoginResponse{
-		Token: token,
-		User:  user.ToResponse(),
+		Token:        token,
+		RefreshToken: refreshToken,
+		User:         user.ToResponse(),
 	}
 
 	return response, nil
 }
 
-// GenerateToken generates a JWT token for a user
-func (s *authService) GenerateToken(userID string) (string, error) {
+// GenerateToken generates a JWT token for a user session
+func (s *authService) GenerateToken(user *model.User, sessionID string) (string, error) {
+	jti, err := security.NewUUID()
+	if err != nil {
+		return "", err
+	}
+
+	version, err := s.revocationStore.TokenVersion(user.ID)
+	if err != nil {
+		return "", err
+	}
+
 	claims := jwt.MapClaims{
-		"user_id": userID,
+		"user_id": user.ID,
+		"role":    user.Role,
+		"typ":     tokenTypeAccess,
+		"jti":     jti,
+		"tv":      version,
+		"sid":     sessionID,
 		"exp":     time.Now().Add(time.Hour * time.Duration(s.config.JWT.ExpirationHours)).Unix(),
 		"iat":     time.Now().Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.config.JWT.Secret))
+	return s.signToken(claims)
+}
+
+// signToken signs claims with the current signing key
+func (s *authService) signToken(claims jwt.MapClaims) (string, error) {
+	key, err := s.keyManager.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	if key.KID != "" {
+		token.Header["kid"] = key.KID
+	}
+	return token.SignedString(key.Key)
+}
+
+// GenerateRefreshToken generates and stores a refresh token in the given family
+func (s *authService) GenerateRefreshToken(userID, familyID string) (string, error) {
+	token, err := security.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	refreshToken := &model.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().Add(time.Hour * time.Duration(s.config.JWT.RefreshHours)),
+	}
+
+	if err := s.refreshTokenRepo.Create(refreshToken); err != nil {
+		return "", err
+	}
+
+	return token, nil
 }
 
 // ValidateToken validates a JWT token
 func (s *authService) ValidateToken(tokenString string) (*jwt.Token, error) {
-	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return []byte(s.config.JWT.Secret), nil
-	})
+	return jwt.Parse(tokenString, s.keyManager.VerificationKey, jwt.WithValidMethods(s.keyManager.ValidMethods()))
 }
 
 // GetUserFromToken extracts user information from a JWT token
 func (s *authService) GetUserFromToken(tokenString string) (*model.User, error) {
+	user, _, err := s.AuthenticateToken(tokenString)
+	return user, err
+}
+
+// AuthenticateToken validates an access token and returns its user and session.
+// The session is nil for tokens issued before sessions existed.
+func (s *authService) AuthenticateToken(tokenString string) (*model.User, *model.Session, error) {
 	token, err := s.ValidateToken(tokenString)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
-		return nil, errors.New("invalid token")
+		return nil, nil, errors.New("invalid token")
+	}
+
+	// Tokens issued before token types existed are access tokens
+	if typ, ok := claims["typ"]; ok && typ != tokenTypeAccess {
+		return nil, nil, errors.New("invalid token")
+	}
+
+	userID, ok := claims["user_id"].(string)
+	if !ok {
+		return nil, nil, errors.New("invalid token claims")
+	}
+
+	if err := s.checkRevocation(userID, claims); err != nil {
+		return nil, nil, err
+	}
+
+	var session *model.Session
+	if sessionID, _ := claims["sid"].(string); sessionID != "" {
+		session, err = s.sessionRepo.GetByID(sessionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil, errors.New("token has been revoked")
+			}
+			return nil, nil, err
+		}
+		if session.RevokedAt != nil {
+			return nil, nil, errors.New("token has been revoked")
+		}
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.IsDisabled() {
+		return nil, nil, errors.New("account disabled")
+	}
+
+	return user, session, nil
+}
+
+// generateMFAChallenge generates a short-lived token proving that the first factor succeeded
+func (s *authService) generateMFAChallenge(userID string) (*model.MFAChallenge, error) {
+	jti, err := security.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(time.Minute * time.Duration(s.config.Auth.MFAChallengeMinutes))
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"typ":     tokenTypeMFA,
+		"jti":     jti,
+		"exp":     expiresAt.Unix(),
+		"iat":     time.Now().Unix(),
+	}
+
+	signed, err := s.signToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &model.MFAChallenge{
+		MFARequired: true,
+		MFAToken:    signed,
+		ExpiresAt:   expiresAt,
+	}
+
+	return challenge, nil
+}
+
+// VerifyMFAToken validates an MFA challenge token and returns its user
+func (s *authService) VerifyMFAToken(tokenString string) (*model.User, error) {
+	token, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["typ"] != tokenTypeMFA {
+		return nil, errors.New("invalid or expired mfa token")
 	}
 
 	userID, ok := claims["user_id"].(string)
 	if !ok {
-		return nil, errors.New("invalid token claims")
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	if err := s.checkRevocation(userID, claims); err != nil {
+		if err.Error() == "token has been revoked" || err.Error() == "invalid token claims" {
+			return nil, errors.New("invalid or expired mfa token")
+		}
+		return nil, err
 	}
 
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid or expired mfa token")
+		}
 		return nil, err
 	}
 
 	return user, nil
 }
+
+// checkRevocation returns an error if the token was revoked individually or
+// issued before all tokens of the user were revoked
+func (s *authService) checkRevocation(userID string, claims jwt.MapClaims) error {
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return errors.New("invalid token claims")
+	}
+
+	revoked, err := s.revocationStore.IsRevoked(jti)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return errors.New("token has been revoked")
+	}
+
+	tokenVersion, _ := claims["tv"].(float64)
+	version, err := s.revocationStore.TokenVersion(userID)
+	if err != nil {
+		return err
+	}
+	if int64(tokenVersion) < version {
+		return errors.New("token has been revoked")
+	}
+
+	return nil
+}
+
+// revokeReusedFamily ends the session of a refresh token family whose rotated
+// token was presented again, so access tokens already issued to it stop working
+func (s *authService) revokeReusedFamily(stored *model.RefreshToken) error {
+	if err := s.refreshTokenRepo.RevokeFamily(stored.FamilyID); err != nil {
+		return err
+	}
+	_, err := s.sessionRepo.Revoke(stored.UserID, stored.FamilyID)
+	return err
+}
+
+// Logout revokes an access token and, if given, the refresh token family it was issued with
+func (s *authService) Logout(tokenString, refreshToken string) error {
+	token, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return errors.New("invalid token")
+	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return errors.New("invalid token claims")
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return errors.New("invalid token claims")
+	}
+
+	if err := s.revocationStore.Revoke(jti, expiresAt.Time); err != nil {
+		return err
+	}
+
+	// Logging out ends the session the token belongs to
+	if sessionID, _ := claims["sid"].(string); sessionID != "" {
+		userID, _ := claims["user_id"].(string)
+		if _, err := s.sessionRepo.Revoke(userID, sessionID); err != nil {
+			return err
+		}
+		if err := s.refreshTokenRepo.RevokeFamily(sessionID); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.refreshTokenRepo.GetByHash(security.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// Only allow revoking refresh tokens of the same user
+	if userID, _ := claims["user_id"].(string); stored.UserID != userID {
+		return nil
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(stored.FamilyID)
+}
+
+// RevokeAllTokens revokes every access, refresh and personal access token of a user
+func (s *authService) RevokeAllTokens(userID string) error {
+	if err := s.revocationStore.RevokeAllForUser(userID); err != nil {
+		return err
+	}
+
+	if err := s.refreshTokenRepo.RevokeByUserID(userID); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeByUserID(userID); err != nil {
+		return err
+	}
+
+	return s.patRepo.RevokeByUserID(userID)
+}
+
+// ForgotPassword emails a password reset link to the user.
+// Unknown email addresses are ignored so that accounts cannot be enumerated.
+func (s *authService) ForgotPassword(email string) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// Only the most recently requested link stays valid
+	if err := s.oneTimeTokenRepo.InvalidateByUserID(user.ID, model.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	ttl := time.Minute * time.Duration(s.config.Auth.PasswordResetMinutes)
+	token, err := s.createOneTimeToken(user.ID, model.TokenPurposePasswordReset, ttl)
+	if err != nil {
+		return err
+	}
+
+	link := s.config.Mail.LinkBaseURL + "/reset-password?token=" + url.QueryEscape(token)
+	return s.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: "Hi " + user.Name + ",\r\n\r\n" +
+			"We received a request to reset your password. Use the link below to choose a new one:\r\n\r\n" +
+			link + "\r\n\r\n" +
+			"The link expires in " + ttl.String() + ". If you did not request this, you can ignore this email.\r\n",
+	})
+}
+
+// ResetPassword sets a new password using a reset token and revokes all existing tokens
+func (s *authService) ResetPassword(token, password string) error {
+	resetToken, err := s.lookupOneTimeToken(model.TokenPurposePasswordReset, token)
+	if err != nil {
+		if err.Error() == "invalid or expired token" {
+			return errors.New("invalid or expired reset token")
+		}
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(resetToken.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("invalid or expired reset token")
+		}
+		return err
+	}
+
+	// The link stays usable if the new password is rejected
+	if err := s.passwordPolicy.Validate(password, user.Email, user.Name); err != nil {
+		return err
+	}
+
+	used, err := s.oneTimeTokenRepo.MarkUsed(resetToken.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.New("invalid or expired reset token")
+	}
+
+	hashedPassword, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	user.Password = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	return s.RevokeAllTokens(user.ID)
+}
+
+// RequestMagicLink emails a single-use passwordless login link to the user.
+// Requests are rate limited per address, and unknown or disabled accounts are
+// ignored so that accounts cannot be enumerated.
+func (s *authService) RequestMagicLink(email string) error {
+	if err := s.magicLinkLimiter.Allow(email); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.IsDisabled() {
+		return nil
+	}
+
+	// Only the most recently requested link stays valid
+	if err := s.oneTimeTokenRepo.InvalidateByUserID(user.ID, model.TokenPurposeMagicLink); err != nil {
+		return err
+	}
+
+	ttl := time.Minute * time.Duration(s.config.Auth.MagicLinkMinutes)
+	token, err := s.createOneTimeToken(user.ID, model.TokenPurposeMagicLink, ttl)
+	if err != nil {
+		return err
+	}
+
+	link := s.config.Mail.LinkBaseURL + "/magic-link?token=" + url.QueryEscape(token)
+	return s.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: "Hi " + user.Name + ",\r\n\r\n" +
+			"Use the link below to log in. It can only be used once:\r\n\r\n" +
+			link + "\r\n\r\n" +
+			"The link expires in " + ttl.String() + ". If you did not request this, you can ignore this email.\r\n",
+	})
+}
+
+// LoginWithMagicLink exchanges a login link for a token pair.
+// Opening the link proves ownership of the address, so it also verifies the email.
+// Users with two-factor authentication still have to complete the MFA challenge.
+func (s *authService) LoginWithMagicLink(token string, client model.ClientInfo) (*model.LoginResponse, *model.MFAChallenge, error) {
+	loginToken, err := s.consumeOneTimeToken(model.TokenPurposeMagicLink, token)
+	if err != nil {
+		if err.Error() == "invalid or expired token" {
+			return nil, nil, errors.New("invalid or expired login link")
+		}
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(loginToken.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("invalid or expired login link")
+		}
+		return nil, nil, err
+	}
+
+	if user.IsDisabled() {
+		return nil, nil, errors.New("account disabled")
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return s.ContinueLogin(user, client, false)
+}
+
+// rehashPassword stores a new hash of a verified password
+func (s *authService) rehashPassword(user *model.User, password string) error {
+	hashedPassword, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	user.Password = hashedPassword
+	return s.userRepo.Update(user)
+}
+
+// SendVerificationEmail emails a new verification link to a user
+func (s *authService) SendVerificationEmail(userID string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return errors.New("email already verified")
+	}
+
+	return s.sendVerificationEmail(user)
+}
+
+// VerifyEmail marks the email address belonging to a verification token as verified
+func (s *authService) VerifyEmail(token string) error {
+	verificationToken, err := s.consumeOneTimeToken(model.TokenPurposeEmailVerification, token)
+	if err != nil {
+		if err.Error() == "invalid or expired token" {
+			return errors.New("invalid or expired verification token")
+		}
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(verificationToken.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("invalid or expired verification token")
+		}
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	return s.userRepo.Update(user)
+}
+
+// sendVerificationEmail invalidates older verification links and emails a new one
+func (s *authService) sendVerificationEmail(user *model.User) error {
+	if err := s.oneTimeTokenRepo.InvalidateByUserID(user.ID, model.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+
+	ttl := time.Hour * time.Duration(s.config.Auth.EmailVerificationHours)
+	token, err := s.createOneTimeToken(user.ID, model.TokenPurposeEmailVerification, ttl)
+	if err != nil {
+		return err
+	}
+
+	link := s.config.Mail.LinkBaseURL + "/verify-email?token=" + url.QueryEscape(token)
+	return s.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: "Hi " + user.Name + ",\r\n\r\n" +
+			"Please confirm your email address by opening the link below:\r\n\r\n" +
+			link + "\r\n\r\n" +
+			"The link expires in " + ttl.String() + ".\r\n",
+	})
+}
+
+// createOneTimeToken generates and stores a one-time token for a user
+func (s *authService) createOneTimeToken(userID string, purpose model.TokenPurpose, ttl time.Duration) (string, error) {
+	token, err := security.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	oneTimeToken := &model.OneTimeToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	if err := s.oneTimeTokenRepo.Create(oneTimeToken); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// lookupOneTimeToken finds a usable one-time token without consuming it
+func (s *authService) lookupOneTimeToken(purpose model.TokenPurpose, token string) (*model.OneTimeToken, error) {
+	stored, err := s.oneTimeTokenRepo.GetByHash(purpose, security.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid or expired token")
+		}
+		return nil, err
+	}
+
+	if !stored.IsUsable() {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	return stored, nil
+}
+
+// consumeOneTimeToken looks up a one-time token and marks it as used
+func (s *authService) consumeOneTimeToken(purpose model.TokenPurpose, token string) (*model.OneTimeToken, error) {
+	stored, err := s.lookupOneTimeToken(purpose, token)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.oneTimeTokenRepo.MarkUsed(stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	return stored, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// fakeRefreshTokenRepo keeps refresh tokens in memory
+type fakeRefreshTokenRepo struct {
+	repository.RefreshTokenRepository
+	tokens map[string]*model.RefreshToken
+}
+
+func (r *fakeRefreshTokenRepo) Create(token *model.RefreshToken) error {
+	token.ID = token.TokenHash
+	r.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) GetByHash(tokenHash string) (*model.RefreshToken, error) {
+	if token, ok := r.tokens[tokenHash]; ok {
+		return token, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeRefreshTokenRepo) MarkRotated(id string) (bool, error) {
+	token, ok := r.tokens[id]
+	if !ok || token.RotatedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.RotatedAt = &now
+	return true, nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeFamily(familyID string) error {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeSessionRepo) Extend(id string, lastSeenAt, expiresAt time.Time) error {
+	return nil
+}
+
+func (r *fakeSessionRepo) Revoke(userID, sessionID string) (bool, error) {
+	session, ok := r.sessions[sessionID]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	return true, nil
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	userRepo := &fakeUserRepo{users: map[string]*model.User{
+		"user-1": {ID: "user-1", Email: "alice@example.com"},
+	}}
+	refreshTokenRepo := &fakeRefreshTokenRepo{tokens: make(map[string]*model.RefreshToken)}
+	sessionRepo := &fakeSessionRepo{sessions: map[string]*model.Session{
+		"session-1": {ID: "session-1", UserID: "user-1", CreatedAt: time.Now()},
+	}}
+	cfg := &config.Config{JWT: config.JWTConfig{
+		Secret:          "test-secret",
+		Algorithm:       AlgorithmHS256,
+		ExpirationHours: 1,
+		RefreshHours:    24,
+	}}
+	keyManager, err := NewKeyManager(nil, cfg)
+	if err != nil {
+		t.Fatalf("NewKeyManager() error = %v", err)
+	}
+	service := NewAuthService(userRepo, refreshTokenRepo, repository.NewMemoryRevocationStore(), nil, nil, sessionRepo, nil, nil, nil, nil, nil, keyManager, cfg)
+
+	rotated, err := service.GenerateRefreshToken("user-1", "session-1")
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken() error = %v", err)
+	}
+	response, err := service.Refresh(rotated)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if _, _, err := service.AuthenticateToken(response.Token); err != nil {
+		t.Fatalf("AuthenticateToken() before reuse error = %v", err)
+	}
+
+	if _, err := service.Refresh(rotated); err == nil || err.Error() != "refresh token reuse detected" {
+		t.Fatalf("Refresh() with a rotated token error = %v, want %q", err, "refresh token reuse detected")
+	}
+
+	if _, _, err := service.AuthenticateToken(response.Token); err == nil || err.Error() != "token has been revoked" {
+		t.Errorf("AuthenticateToken() after reuse error = %v, want %q", err, "token has been revoked")
+	}
+	if _, err := service.Refresh(response.RefreshToken); err == nil || err.Error() != "invalid refresh token" {
+		t.Errorf("Refresh() with the latest token error = %v, want %q", err, "invalid refresh token")
+	}
+}
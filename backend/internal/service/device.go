@@ -0,0 +1,244 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"log"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+	"gorm.io/gorm"
+)
+
+// DeviceCodeGrantType is the grant type of the device authorization grant
+const DeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// userCodeAlphabet leaves out vowels and easily confused characters (RFC 8628 section 6.1)
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// userCodeLength is the number of characters in a user code, shown as XXXX-XXXX
+const userCodeLength = 8
+
+// slowDownSeconds is added to the polling interval whenever a device polls too fast
+const slowDownSeconds = 5
+
+// DeviceService defines the interface for the OAuth 2.0 device authorization grant (RFC 8628)
+type DeviceService interface {
+	Authorize(req *model.DeviceCodeRequest) (*model.DeviceCodeResponse, error)
+	PollToken(req *model.DeviceTokenRequest, client model.ClientInfo) (*model.DeviceTokenResponse, error)
+	Decide(userID string, req *model.DeviceApprovalRequest) error
+}
+
+// deviceService implements DeviceService interface
+type deviceService struct {
+	deviceRepo      repository.DeviceAuthorizationRepository
+	userRepo        repository.UserRepository
+	authService     AuthService
+	approvalLimiter RateLimiter
+	config          *config.Config
+}
+
+// NewDeviceService creates a new device authorization service
+func NewDeviceService(deviceRepo repository.DeviceAuthorizationRepository, userRepo repository.UserRepository, authService AuthService, approvalLimiter RateLimiter, config *config.Config) DeviceService {
+	return &deviceService{
+		deviceRepo:      deviceRepo,
+		userRepo:        userRepo,
+		authService:     authService,
+		approvalLimiter: approvalLimiter,
+		config:          config,
+	}
+}
+
+// Authorize starts a device authorization and returns the codes to show on the device
+func (s *deviceService) Authorize(req *model.DeviceCodeRequest) (*model.DeviceCodeResponse, error) {
+	deviceCode, err := security.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	userCode, err := newUserCode()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.deviceRepo.DeleteExpired(now); err != nil {
+		log.Printf("Failed to delete expired device authorizations: %v", err)
+	}
+
+	lifetime := time.Duration(s.config.Auth.DeviceCodeMinutes) * time.Minute
+	authorization := &model.DeviceAuthorization{
+		DeviceCodeHash:  security.HashToken(deviceCode),
+		UserCode:        userCode,
+		ClientName:      strings.TrimSpace(req.ClientID),
+		Status:          model.DeviceAuthorizationPending,
+		IntervalSeconds: s.config.Auth.DevicePollIntervalSeconds,
+		ExpiresAt:       now.Add(lifetime),
+	}
+	if err := s.deviceRepo.Create(authorization); err != nil {
+		return nil, err
+	}
+
+	displayCode := formatUserCode(userCode)
+	verificationURI := s.config.Mail.LinkBaseURL + "/device"
+	return &model.DeviceCodeResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                displayCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + url.QueryEscape(displayCode),
+		ExpiresIn:               int(lifetime.Seconds()),
+		Interval:                authorization.IntervalSeconds,
+	}, nil
+}
+
+// PollToken answers a device polling the token endpoint. Until the user has decided it
+// returns "authorization_pending", and "slow_down" if the device polls faster than allowed.
+// An approved authorization is redeemed exactly once for a new session.
+func (s *deviceService) PollToken(req *model.DeviceTokenRequest, client model.ClientInfo) (*model.DeviceTokenResponse, error) {
+	if req.GrantType != DeviceCodeGrantType {
+		return nil, errors.New("unsupported_grant_type")
+	}
+
+	authorization, err := s.deviceRepo.GetByDeviceCodeHash(security.HashToken(req.DeviceCode))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid_grant")
+		}
+		return nil, err
+	}
+
+	if authorization.IsExpired() {
+		return nil, errors.New("expired_token")
+	}
+
+	now := time.Now()
+	interval := authorization.IntervalSeconds
+	tooFast := authorization.LastPolledAt != nil &&
+		now.Sub(*authorization.LastPolledAt) < time.Duration(interval)*time.Second
+	if tooFast {
+		interval += slowDownSeconds
+	}
+	if err := s.deviceRepo.RecordPoll(authorization.ID, now, interval); err != nil {
+		return nil, err
+	}
+	if tooFast {
+		return nil, errors.New("slow_down")
+	}
+
+	switch authorization.Status {
+	case model.DeviceAuthorizationPending:
+		return nil, errors.New("authorization_pending")
+	case model.DeviceAuthorizationDenied:
+		if _, err := s.deviceRepo.Delete(authorization.ID); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("access_denied")
+	}
+
+	// Deleting the approval first makes sure concurrent polls cannot redeem it twice
+	deleted, err := s.deviceRepo.Delete(authorization.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted || authorization.UserID == nil {
+		return nil, errors.New("invalid_grant")
+	}
+
+	user, err := s.userRepo.GetByID(*authorization.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid_grant")
+		}
+		return nil, err
+	}
+
+	if client.UserAgent == "" && authorization.ClientName != "" {
+		client.UserAgent = authorization.ClientName
+	}
+	response, err := s.authService.IssueLoginResponse(user, client)
+	if err != nil {
+		if err.Error() == "account disabled" {
+			return nil, errors.New("access_denied")
+		}
+		return nil, err
+	}
+
+	return &model.DeviceTokenResponse{
+		AccessToken:  response.Token,
+		TokenType:    "Bearer",
+		ExpiresIn:    s.config.JWT.ExpirationHours * 3600,
+		RefreshToken: response.RefreshToken,
+		User:         response.User,
+	}, nil
+}
+
+// Decide lets a logged-in user approve or deny the device showing a user code.
+// Attempts are rate limited per user so user codes cannot be guessed.
+func (s *deviceService) Decide(userID string, req *model.DeviceApprovalRequest) error {
+	if err := s.approvalLimiter.Allow(userID); err != nil {
+		return err
+	}
+
+	authorization, err := s.deviceRepo.GetByUserCode(normalizeUserCode(req.UserCode))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("invalid or expired user code")
+		}
+		return err
+	}
+
+	if authorization.IsExpired() || authorization.Status != model.DeviceAuthorizationPending {
+		return errors.New("invalid or expired user code")
+	}
+
+	status := model.DeviceAuthorizationApproved
+	if req.Deny {
+		status = model.DeviceAuthorizationDenied
+	}
+
+	decided, err := s.deviceRepo.Decide(authorization.ID, userID, status)
+	if err != nil {
+		return err
+	}
+	if !decided {
+		return errors.New("invalid or expired user code")
+	}
+
+	return nil
+}
+
+// newUserCode returns a random user code in its normalized form
+func newUserCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+	code := make([]byte, userCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = userCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// normalizeUserCode uppercases a user code and drops separators and other punctuation
+func normalizeUserCode(userCode string) string {
+	var normalized strings.Builder
+	for _, r := range strings.ToUpper(userCode) {
+		if r >= 'A' && r <= 'Z' {
+			normalized.WriteRune(r)
+		}
+	}
+	return normalized.String()
+}
+
+// formatUserCode splits a normalized user code into two halves for display
+func formatUserCode(userCode string) string {
+	half := len(userCode) / 2
+	return userCode[:half] + "-" + userCode[half:]
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"time"
+)
+
+// TooManyRequestsError is returned when a caller has to wait before trying again
+type TooManyRequestsError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *TooManyRequestsError) Error() string {
+	return e.Message
+}
+
+// PasswordViolation describes one way in which a password fails the password policy
+type PasswordViolation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError is returned when a password does not satisfy the password policy
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+// Error implements the error interface
+func (e *PasswordPolicyError) Error() string {
+	return "password does not meet requirements"
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"log"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+)
+
+// Supported JWT signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// keyReloadInterval limits how often an unknown kid triggers a reload from the database
+const keyReloadInterval = 10 * time.Second
+
+// SigningKey is a key that can sign new tokens
+type SigningKey struct {
+	KID    string
+	Method jwt.SigningMethod
+	Key    interface{}
+}
+
+// KeyManager defines the interface for JWT signing key management
+type KeyManager interface {
+	SigningKey() (*SigningKey, error)
+	VerificationKey(token *jwt.Token) (interface{}, error)
+	ValidMethods() []string
+	JWKS() (*model.JWKS, error)
+	Rotate() error
+	StartRotation()
+}
+
+// cachedKey is a parsed signing key
+type cachedKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	public    crypto.PublicKey
+	createdAt time.Time
+	active    bool
+}
+
+// keyManager implements KeyManager interface.
+// With HS256 it signs with the shared secret; with RS256 or EdDSA it keeps
+// asymmetric keys in the database so every replica signs with the same key
+// and can verify tokens signed by retired keys during their grace period.
+type keyManager struct {
+	repo          repository.SigningKeyRepository
+	config        *config.Config
+	encryptionKey []byte
+
+	mu       sync.RWMutex
+	keys     map[string]*cachedKey
+	active   *cachedKey
+	loadedAt time.Time
+}
+
+// NewKeyManager creates a new key manager and makes sure an active key exists
+func NewKeyManager(repo repository.SigningKeyRepository, config *config.Config) (KeyManager, error) {
+	m := &keyManager{
+		repo:          repo,
+		config:        config,
+		encryptionKey: security.DeriveKey(config.Auth.EncryptionKey),
+		keys:          make(map[string]*cachedKey),
+	}
+
+	switch config.JWT.Algorithm {
+	case AlgorithmHS256:
+		return m, nil
+	case AlgorithmRS256, AlgorithmEdDSA:
+	default:
+		return nil, errors.New("unsupported jwt algorithm: " + config.JWT.Algorithm)
+	}
+
+	if err := m.rotateIfDue(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// SigningKey returns the key new tokens are signed with
+func (m *keyManager) SigningKey() (*SigningKey, error) {
+	if m.config.JWT.Algorithm == AlgorithmHS256 {
+		return &SigningKey{Method: jwt.SigningMethodHS256, Key: []byte(m.config.JWT.Secret)}, nil
+	}
+
+	m.mu.RLock()
+	active := m.active
+	m.mu.RUnlock()
+
+	if active == nil {
+		return nil, errors.New("no active signing key")
+	}
+
+	return &SigningKey{KID: active.kid, Method: active.method, Key: active.private}, nil
+}
+
+// VerificationKey returns the key that verifies a parsed token
+func (m *keyManager) VerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		// Tokens without a kid are signed with the shared secret
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok && m.acceptsHMAC() {
+			return []byte(m.config.JWT.Secret), nil
+		}
+		return nil, errors.New("invalid signing method")
+	}
+
+	key, err := m.lookup(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+
+	return key.public, nil
+}
+
+// ValidMethods returns the algorithms accepted when parsing tokens
+func (m *keyManager) ValidMethods() []string {
+	methods := []string{}
+	if m.config.JWT.Algorithm != AlgorithmHS256 {
+		methods = append(methods, m.config.JWT.Algorithm)
+	}
+	if m.acceptsHMAC() {
+		methods = append(methods, AlgorithmHS256)
+	}
+
+	// Keys of a previously configured algorithm keep verifying until they expire
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, key := range m.keys {
+		if !slices.Contains(methods, key.method.Alg()) {
+			methods = append(methods, key.method.Alg())
+		}
+	}
+
+	return methods
+}
+
+// JWKS returns the public keys that currently verify tokens
+func (m *keyManager) JWKS() (*model.JWKS, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	jwks := &model.JWKS{Keys: []model.JWK{}}
+	for _, key := range m.keys {
+		jwk, err := toJWK(key)
+		if err != nil {
+			return nil, err
+		}
+		jwks.Keys = append(jwks.Keys, *jwk)
+	}
+
+	return jwks, nil
+}
+
+// Rotate generates a new signing key and retires the current ones.
+// Retired keys keep verifying for the configured grace period.
+func (m *keyManager) Rotate() error {
+	if m.config.JWT.Algorithm == AlgorithmHS256 {
+		return errors.New("key rotation requires an asymmetric jwt algorithm")
+	}
+
+	key, err := m.generateKey(m.config.JWT.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	if err := m.repo.Create(key); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	retiring := []string{}
+	for kid, cached := range m.keys {
+		if cached.active {
+			retiring = append(retiring, kid)
+		}
+	}
+	m.mu.RUnlock()
+
+	now := time.Now()
+	for _, kid := range retiring {
+		if err := m.repo.Retire(kid, now, now.Add(m.gracePeriod())); err != nil {
+			return err
+		}
+	}
+
+	if err := m.repo.DeleteExpired(); err != nil {
+		return err
+	}
+
+	return m.reload()
+}
+
+// StartRotation periodically reloads keys and rotates the active key when it is due
+func (m *keyManager) StartRotation() {
+	if m.config.JWT.Algorithm == AlgorithmHS256 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := m.rotateIfDue(); err != nil {
+				log.Printf("Failed to rotate signing keys: %v", err)
+			}
+		}
+	}()
+}
+
+// rotateIfDue reloads keys, since another replica may have rotated them,
+// and rotates if there is no active key or it is older than the rotation interval
+func (m *keyManager) rotateIfDue() error {
+	if err := m.reload(); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	active := m.active
+	m.mu.RUnlock()
+
+	interval := time.Hour * time.Duration(m.config.JWT.KeyRotationHours)
+	if active != nil && active.method.Alg() == m.config.JWT.Algorithm && time.Since(active.createdAt) < interval {
+		return nil
+	}
+
+	return m.Rotate()
+}
+
+// reload replaces the cached keys with the valid keys from the database
+func (m *keyManager) reload() error {
+	stored, err := m.repo.GetValid()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*cachedKey, len(stored))
+	var active *cachedKey
+	for i := range stored {
+		key, err := m.parseKey(&stored[i])
+		if err != nil {
+			return err
+		}
+		keys[key.kid] = key
+
+		// Keys are ordered newest first
+		if key.active && active == nil && key.method.Alg() == m.config.JWT.Algorithm {
+			active = key
+		}
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.active = active
+	m.loadedAt = time.Now()
+	m.mu.Unlock()
+
+	return nil
+}
+
+// lookup finds a key by kid, reloading from the database for unknown kids
+func (m *keyManager) lookup(kid string) (*cachedKey, error) {
+	m.mu.RLock()
+	key, ok := m.keys[kid]
+	loadedAt := m.loadedAt
+	m.mu.RUnlock()
+
+	if ok {
+		return key, nil
+	}
+
+	if time.Since(loadedAt) < keyReloadInterval {
+		return nil, errors.New("unknown signing key")
+	}
+
+	if err := m.reload(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if key, ok := m.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, errors.New("unknown signing key")
+}
+
+// gracePeriod returns how long a retired key keeps verifying tokens.
+// It is never shorter than the lifetime of the tokens it signed.
+func (m *keyManager) gracePeriod() time.Duration {
+	grace := time.Hour * time.Duration(m.config.JWT.KeyGraceHours)
+	lifetime := time.Hour * time.Duration(m.config.JWT.ExpirationHours)
+	return max(grace, lifetime)
+}
+
+func (m *keyManager) acceptsHMAC() bool {
+	return m.config.JWT.Algorithm == AlgorithmHS256 || m.config.JWT.AcceptHS256
+}
+
+// generateKey creates a new key pair and returns it with the private key encrypted
+func (m *keyManager) generateKey(algorithm string) (*model.SigningKey, error) {
+	var private crypto.Signer
+	switch algorithm {
+	case AlgorithmRS256:
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	case AlgorithmEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	default:
+		return nil, errors.New("unsupported jwt algorithm: " + algorithm)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})
+	encrypted, err := security.Encrypt(m.encryptionKey, string(privatePEM))
+	if err != nil {
+		return nil, err
+	}
+
+	kid, err := security.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &model.SigningKey{
+		KID:        kid,
+		Algorithm:  algorithm,
+		PrivateKey: encrypted,
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+	}
+
+	return key, nil
+}
+
+// parseKey decodes a stored key. Private keys are only decrypted for active keys.
+func (m *keyManager) parseKey(stored *model.SigningKey) (*cachedKey, error) {
+	method := jwt.GetSigningMethod(stored.Algorithm)
+	if method == nil {
+		return nil, errors.New("unsupported jwt algorithm: " + stored.Algorithm)
+	}
+
+	block, _ := pem.Decode([]byte(stored.PublicKey))
+	if block == nil {
+		return nil, errors.New("invalid public key for kid " + stored.KID)
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &cachedKey{
+		kid:       stored.KID,
+		method:    method,
+		public:    public,
+		createdAt: stored.CreatedAt,
+		active:    stored.IsActive(),
+	}
+
+	if key.active {
+		privatePEM, err := security.Decrypt(m.encryptionKey, stored.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode([]byte(privatePEM))
+		if block == nil {
+			return nil, errors.New("invalid private key for kid " + stored.KID)
+		}
+		private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("invalid private key for kid " + stored.KID)
+		}
+		key.private = signer
+	}
+
+	return key, nil
+}
+
+// toJWK converts a public key to its JWK representation
+func toJWK(key *cachedKey) (*model.JWK, error) {
+	jwk := &model.JWK{
+		Use: "sig",
+		Kid: key.kid,
+		Alg: key.method.Alg(),
+	}
+
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	default:
+		return nil, errors.New("unsupported public key type")
+	}
+
+	return jwk, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+)
+
+// LoginThrottler defines the interface for brute-force protection on login
+type LoginThrottler interface {
+	Reserve(email, clientIP string) error
+	Release(clientIP string) error
+	RecordSuccess(email, clientIP string) error
+}
+
+// loginThrottler implements LoginThrottler interface.
+// Attempts are counted per email and per client IP before the credentials
+// are checked, so concurrent requests cannot all slip past the limit. After
+// a few attempts every further one has to wait exponentially longer, and
+// exceeding the configured threshold locks the email or IP out for a while.
+type loginThrottler struct {
+	store  repository.LoginAttemptStore
+	config *config.Config
+}
+
+// NewLoginThrottler creates a new login throttler
+func NewLoginThrottler(store repository.LoginAttemptStore, config *config.Config) LoginThrottler {
+	return &loginThrottler{
+		store:  store,
+		config: config,
+	}
+}
+
+// Reserve counts a login attempt before its credentials are checked. It returns a
+// *TooManyRequestsError if the email or client IP may not attempt a login yet.
+// A reservation that ends in a failure stays counted; successes give it back.
+func (t *loginThrottler) Reserve(email, clientIP string) error {
+	if err := t.check(email, clientIP); err != nil {
+		return err
+	}
+
+	if err := t.reserve(emailAttemptKey(email), t.config.Auth.MaxLoginAttempts); err != nil {
+		return err
+	}
+
+	if clientIP == "" {
+		return nil
+	}
+
+	return t.reserve(ipAttemptKey(clientIP), t.config.Auth.MaxLoginAttemptsPerIP)
+}
+
+// Release gives back the attempt reserved for a client IP once the password has been
+// verified. The email keeps its count until every factor has been checked.
+func (t *loginThrottler) Release(clientIP string) error {
+	if clientIP == "" {
+		return nil
+	}
+	return t.store.Release(ipAttemptKey(clientIP))
+}
+
+// RecordSuccess clears the attempts of an email and gives back the attempt reserved
+// for the client IP. Earlier IP failures are left alone so that one valid account
+// cannot be used to reset the limit for guessing others.
+func (t *loginThrottler) RecordSuccess(email, clientIP string) error {
+	if err := t.store.Reset(emailAttemptKey(email)); err != nil {
+		return err
+	}
+	return t.Release(clientIP)
+}
+
+// check rejects keys that are locked out or have to wait after their last attempt
+func (t *loginThrottler) check(email, clientIP string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+
+	for _, key := range t.keys(email, clientIP) {
+		attempt, err := t.store.Get(key)
+		if err != nil {
+			return err
+		}
+		if attempt == nil {
+			continue
+		}
+
+		if attempt.IsLocked(now) {
+			retryAfter = max(retryAfter, attempt.LockedUntil.Sub(now))
+			continue
+		}
+
+		if now.Sub(attempt.WindowStart) > t.window() {
+			continue
+		}
+
+		if wait := attempt.LastFailureAt.Add(t.delay(attempt.Failures)).Sub(now); wait > 0 {
+			retryAfter = max(retryAfter, wait)
+		}
+	}
+
+	if retryAfter > 0 {
+		return &TooManyRequestsError{
+			Message:    "too many login attempts",
+			RetryAfter: retryAfter,
+		}
+	}
+
+	return nil
+}
+
+// reserve atomically counts an attempt for a key and locks the key out once the
+// count goes over its limit
+func (t *loginThrottler) reserve(key string, limit int) error {
+	attempt, err := t.store.RecordFailure(key, t.window())
+	if err != nil {
+		return err
+	}
+	if attempt.Failures <= limit {
+		return nil
+	}
+
+	lockout := time.Minute * time.Duration(t.config.Auth.LockoutMinutes)
+	if err := t.store.Lock(key, time.Now().Add(lockout)); err != nil {
+		return err
+	}
+	return &TooManyRequestsError{
+		Message:    "too many login attempts",
+		RetryAfter: lockout,
+	}
+}
+
+// delay returns how long to wait after the given number of failures
+func (t *loginThrottler) delay(failures int) time.Duration {
+	free := t.config.Auth.LoginDelayAfter
+	if failures < free {
+		return 0
+	}
+
+	maxDelay := time.Second * time.Duration(t.config.Auth.LoginMaxDelaySeconds)
+	delay := time.Second
+	for i := free; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+func (t *loginThrottler) window() time.Duration {
+	return time.Minute * time.Duration(t.config.Auth.LoginAttemptWindowMinutes)
+}
+
+func (t *loginThrottler) keys(email, clientIP string) []string {
+	keys := []string{emailAttemptKey(email)}
+	if clientIP != "" {
+		keys = append(keys, ipAttemptKey(clientIP))
+	}
+	return keys
+}
+
+func emailAttemptKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptKey(clientIP string) string {
+	return "ip:" + clientIP
+}
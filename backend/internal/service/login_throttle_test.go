@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+)
+
+func newTestLoginThrottler() LoginThrottler {
+	return NewLoginThrottler(repository.NewMemoryLoginAttemptStore(), &config.Config{Auth: config.AuthConfig{
+		MaxLoginAttempts:          5,
+		MaxLoginAttemptsPerIP:     50,
+		LoginAttemptWindowMinutes: 15,
+		LoginDelayAfter:           100,
+		LockoutMinutes:            15,
+	}})
+}
+
+func TestLoginThrottlerReserveConcurrent(t *testing.T) {
+	throttler := newTestLoginThrottler()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := throttler.Reserve("alice@example.com", "203.0.113.1")
+			var tooMany *TooManyRequestsError
+			if err != nil && !errors.As(err, &tooMany) {
+				t.Errorf("Reserve() error = %v", err)
+			}
+			if err == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 5 {
+		t.Errorf("Reserve() allowed %d concurrent attempts, want 5", allowed)
+	}
+	if err := throttler.Reserve("alice@example.com", "203.0.113.1"); err == nil {
+		t.Error("Reserve() after the limit error = nil, want a lockout")
+	}
+}
+
+func TestLoginThrottlerRecordSuccess(t *testing.T) {
+	throttler := newTestLoginThrottler()
+
+	for range 4 {
+		if err := throttler.Reserve("alice@example.com", "203.0.113.1"); err != nil {
+			t.Fatalf("Reserve() error = %v", err)
+		}
+	}
+	if err := throttler.RecordSuccess("alice@example.com", "203.0.113.1"); err != nil {
+		t.Fatalf("RecordSuccess() error = %v", err)
+	}
+
+	for range 5 {
+		if err := throttler.Reserve("alice@example.com", "203.0.113.1"); err != nil {
+			t.Fatalf("Reserve() after a success error = %v", err)
+		}
+	}
+}
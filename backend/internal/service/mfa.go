@@ -0,0 +1,294 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+)
+
+// recoveryCodeCount is the number of recovery codes issued on enrollment
+const recoveryCodeCount = 10
+
+// MFAService defines the interface for two-factor authentication operations
+type MFAService interface {
+	Setup(userID string) (*model.TOTPSetupResponse, error)
+	Confirm(userID, code string) (*model.TOTPConfirmResponse, error)
+	Disable(userID, sessionID, password, code, clientIP string) error
+	CompleteLogin(mfaToken, code string, client model.ClientInfo) (*model.LoginResponse, error)
+}
+
+// mfaService implements MFAService interface
+type mfaService struct {
+	userRepo         repository.UserRepository
+	recoveryCodeRepo repository.MFARecoveryCodeRepository
+	sessionRepo      repository.SessionRepository
+	authService      AuthService
+	loginThrottler   LoginThrottler
+	passwordHasher   security.PasswordHasher
+	config           *config.Config
+}
+
+// NewMFAService creates a new MFA service
+func NewMFAService(userRepo repository.UserRepository, recoveryCodeRepo repository.MFARecoveryCodeRepository, sessionRepo repository.SessionRepository, authService AuthService, loginThrottler LoginThrottler, passwordHasher security.PasswordHasher, config *config.Config) MFAService {
+	return &mfaService{
+		userRepo:         userRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		sessionRepo:      sessionRepo,
+		authService:      authService,
+		loginThrottler:   loginThrottler,
+		passwordHasher:   passwordHasher,
+		config:           config,
+	}
+}
+
+// Setup starts TOTP enrollment by generating a new secret for the user.
+// The secret only takes effect once it is confirmed with a valid code.
+func (s *mfaService) Setup(userID string) (*model.TOTPSetupResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsTOTPEnabled() {
+		return nil, errors.New("two-factor authentication already enabled")
+	}
+
+	secret, err := security.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := security.Encrypt(security.DeriveKey(s.config.Auth.EncryptionKey), secret)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = encrypted
+	user.TOTPLastStep = 0
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	response := &model.TOTPSetupResponse{
+		Secret: secret,
+		URI:    security.TOTPKeyURI(s.config.Auth.MFAIssuer, user.Email, secret),
+	}
+
+	return response, nil
+}
+
+// Confirm enables TOTP after the user proves their authenticator works and
+// returns a fresh set of recovery codes
+func (s *mfaService) Confirm(userID, code string) (*model.TOTPConfirmResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsTOTPEnabled() {
+		return nil, errors.New("two-factor authentication already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor authentication setup not started")
+	}
+
+	ok, err := s.verifyTOTP(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	codes, err := s.regenerateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TOTPEnabledAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return &model.TOTPConfirmResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns off two-factor authentication after re-checking both factors.
+// Attempts count towards the login throttle and the error does not tell which
+// factor was wrong. Accounts without a password must have signed in recently.
+func (s *mfaService) Disable(userID, sessionID, password, code, clientIP string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsTOTPEnabled() {
+		return errors.New("two-factor authentication not enabled")
+	}
+
+	if !user.HasPassword() {
+		if err := checkRecentLogin(s.sessionRepo, user.ID, sessionID, s.config.Auth.ReauthMinutes); err != nil {
+			return err
+		}
+	}
+
+	if err := s.loginThrottler.Reserve(user.Email, clientIP); err != nil {
+		return err
+	}
+
+	match := true
+	if user.HasPassword() {
+		match, err = s.passwordHasher.Verify(user.Password, password)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Recovery codes are only used up once the password is known to be right
+	ok := false
+	if match {
+		ok, err = s.verifyCode(user, code)
+		if err != nil {
+			return err
+		}
+	}
+	if !ok {
+		return errors.New("invalid password or two-factor code")
+	}
+
+	if err := s.loginThrottler.RecordSuccess(user.Email, clientIP); err != nil {
+		return err
+	}
+
+	user.TOTPSecret = ""
+	user.TOTPEnabledAt = nil
+	user.TOTPLastStep = 0
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	return s.recoveryCodeRepo.DeleteByUserID(user.ID)
+}
+
+// CompleteLogin exchanges an MFA challenge and a TOTP or recovery code for a token pair.
+// Wrong codes count towards the same lockout as wrong passwords.
+func (s *mfaService) CompleteLogin(mfaToken, code string, client model.ClientInfo) (*model.LoginResponse, error) {
+	user, err := s.authService.VerifyMFAToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsTOTPEnabled() {
+		return nil, errors.New("invalid or expired mfa token")
+	}
+
+	if err := s.loginThrottler.Reserve(user.Email, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.verifyCode(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	if err := s.loginThrottler.RecordSuccess(user.Email, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	// The challenge is single-use
+	if err := s.authService.Logout(mfaToken, ""); err != nil {
+		return nil, err
+	}
+
+	return s.authService.IssueLoginResponse(user, client)
+}
+
+// verifyCode accepts either a current TOTP code or an unused recovery code
+func (s *mfaService) verifyCode(user *model.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == 6 {
+		return s.verifyTOTP(user, code)
+	}
+	return s.useRecoveryCode(user.ID, code)
+}
+
+// verifyTOTP checks a TOTP code and records its time step to prevent replays
+func (s *mfaService) verifyTOTP(user *model.User, code string) (bool, error) {
+	secret, err := security.Decrypt(security.DeriveKey(s.config.Auth.EncryptionKey), user.TOTPSecret)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := security.ValidateTOTP(secret, code, time.Now(), 1)
+	if !ok || step <= user.TOTPLastStep {
+		return false, nil
+	}
+
+	// Another request may have used the code since the user was loaded
+	advanced, err := s.userRepo.AdvanceTOTPStep(user.ID, step)
+	if err != nil || !advanced {
+		return false, err
+	}
+
+	user.TOTPLastStep = step
+	return true, nil
+}
+
+// useRecoveryCode consumes a matching unused recovery code
+func (s *mfaService) useRecoveryCode(userID, code string) (bool, error) {
+	codeHash := security.HashToken(normalizeRecoveryCode(code))
+
+	codes, err := s.recoveryCodeRepo.GetUnusedByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, recoveryCode := range codes {
+		if recoveryCode.CodeHash == codeHash {
+			return s.recoveryCodeRepo.MarkUsed(recoveryCode.ID)
+		}
+	}
+
+	return false, nil
+}
+
+// regenerateRecoveryCodes replaces the recovery codes of a user and returns them in plain text
+func (s *mfaService) regenerateRecoveryCodes(userID string) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	records := make([]model.MFARecoveryCode, recoveryCodeCount)
+
+	for i := range codes {
+		raw, err := security.RandomCode(10)
+		if err != nil {
+			return nil, err
+		}
+		code := raw[:5] + "-" + raw[5:]
+
+		codes[i] = code
+		records[i] = model.MFARecoveryCode{
+			UserID:   userID,
+			CodeHash: security.HashToken(normalizeRecoveryCode(code)),
+		}
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceForUser(userID, records); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// normalizeRecoveryCode makes recovery code comparison case and separator insensitive
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
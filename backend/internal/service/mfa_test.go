@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+)
+
+func (r *fakeUserRepo) Update(user *model.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) AdvanceTOTPStep(id string, step int64) (bool, error) {
+	user, ok := r.users[id]
+	if !ok || step <= user.TOTPLastStep {
+		return false, nil
+	}
+	user.TOTPLastStep = step
+	return true, nil
+}
+
+// fakeRecoveryCodeRepo has no recovery codes
+type fakeRecoveryCodeRepo struct {
+	repository.MFARecoveryCodeRepository
+}
+
+func (r *fakeRecoveryCodeRepo) GetUnusedByUserID(userID string) ([]model.MFARecoveryCode, error) {
+	return nil, nil
+}
+
+func (r *fakeRecoveryCodeRepo) DeleteByUserID(userID string) error {
+	return nil
+}
+
+func TestMFADisable(t *testing.T) {
+	const encryptionKey = "test-encryption-key"
+	secret, err := security.NewTOTPSecret()
+	if err != nil {
+		t.Fatalf("NewTOTPSecret() error = %v", err)
+	}
+	encrypted, err := security.Encrypt(security.DeriveKey(encryptionKey), secret)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	code, err := security.TOTPCode(secret, security.TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatalf("TOTPCode() error = %v", err)
+	}
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+
+	hasher, err := security.NewPasswordHasher(security.PasswordAlgorithmBcrypt, security.Argon2Params{}, 4)
+	if err != nil {
+		t.Fatalf("NewPasswordHasher() error = %v", err)
+	}
+	hash, err := hasher.Hash("password123")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		password  string
+		hash      string
+		sessionID string
+		code      string
+		wantErr   string
+	}{
+		{"right password and code", "password123", hash, "", code, ""},
+		{"wrong password", "wrong-password", hash, "", code, "invalid password or two-factor code"},
+		{"wrong code", "password123", hash, "", wrongCode, "invalid password or two-factor code"},
+		{"no password, fresh session", "", "", "fresh", code, ""},
+		{"no password, stale session", "", "", "stale", code, "recent login required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enabledAt := time.Now()
+			userRepo := &fakeUserRepo{users: map[string]*model.User{
+				"user-1": {ID: "user-1", Email: "alice@example.com", Password: tt.hash, TOTPSecret: encrypted, TOTPEnabledAt: &enabledAt},
+			}}
+			sessionRepo := &fakeSessionRepo{sessions: map[string]*model.Session{
+				"fresh": {ID: "fresh", UserID: "user-1", CreatedAt: time.Now().Add(-time.Minute)},
+				"stale": {ID: "stale", UserID: "user-1", CreatedAt: time.Now().Add(-time.Hour)},
+			}}
+			cfg := &config.Config{Auth: config.AuthConfig{EncryptionKey: encryptionKey, ReauthMinutes: 10}}
+			service := NewMFAService(userRepo, &fakeRecoveryCodeRepo{}, sessionRepo, nil, newTestLoginThrottler(), hasher, cfg)
+
+			err := service.Disable("user-1", tt.sessionID, tt.password, tt.code, "203.0.113.1")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Disable() error = %v", err)
+				}
+				if userRepo.users["user-1"].IsTOTPEnabled() {
+					t.Error("Disable() left two-factor authentication enabled")
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Disable() error = %v, want %q", err, tt.wantErr)
+			}
+			if !userRepo.users["user-1"].IsTOTPEnabled() {
+				t.Error("Disable() turned off two-factor authentication after an error")
+			}
+			if tt.password != "password123" && userRepo.users["user-1"].TOTPLastStep != 0 {
+				t.Error("Disable() used up the code without a valid password")
+			}
+		})
+	}
+}
+
+func TestMFADisableThrottled(t *testing.T) {
+	enabledAt := time.Now()
+	hasher, err := security.NewPasswordHasher(security.PasswordAlgorithmBcrypt, security.Argon2Params{}, 4)
+	if err != nil {
+		t.Fatalf("NewPasswordHasher() error = %v", err)
+	}
+	hash, err := hasher.Hash("password123")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	userRepo := &fakeUserRepo{users: map[string]*model.User{
+		"user-1": {ID: "user-1", Email: "alice@example.com", Password: hash, TOTPEnabledAt: &enabledAt},
+	}}
+	service := NewMFAService(userRepo, &fakeRecoveryCodeRepo{}, &fakeSessionRepo{}, nil, newTestLoginThrottler(), hasher, &config.Config{})
+
+	for range 5 {
+		if err := service.Disable("user-1", "", "wrong-password", "000000", "203.0.113.1"); err == nil || err.Error() != "invalid password or two-factor code" {
+			t.Fatalf("Disable() error = %v, want %q", err, "invalid password or two-factor code")
+		}
+	}
+
+	var tooMany *TooManyRequestsError
+	if err := service.Disable("user-1", "", "password123", "000000", "203.0.113.1"); !errors.As(err, &tooMany) {
+		t.Errorf("Disable() after the limit error = %v, want a *TooManyRequestsError", err)
+	}
+}
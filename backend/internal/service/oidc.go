@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/oidc"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+	"gorm.io/gorm"
+)
+
+// OIDCService defines the interface for OpenID Connect single sign-on
+type OIDCService interface {
+	AuthorizationURL(ctx context.Context) (string, error)
+	CompleteLogin(ctx context.Context, req *model.OIDCCallbackRequest, client model.ClientInfo) (*model.LoginResponse, *model.MFAChallenge, error)
+}
+
+// oidcService implements OIDCService interface
+type oidcService struct {
+	provider    *oidc.Provider
+	oidcRepo    repository.OIDCRepository
+	userRepo    repository.UserRepository
+	authService AuthService
+	config      *config.Config
+}
+
+// NewOIDCService creates a new OpenID Connect service
+func NewOIDCService(oidcRepo repository.OIDCRepository, userRepo repository.UserRepository, authService AuthService, config *config.Config) OIDCService {
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       config.OIDC.Issuer,
+		ClientID:     config.OIDC.ClientID,
+		ClientSecret: config.OIDC.ClientSecret,
+		RedirectURL:  config.OIDC.RedirectURL,
+		Scopes:       config.OIDC.Scopes,
+	})
+
+	return &oidcService{
+		provider:    provider,
+		oidcRepo:    oidcRepo,
+		userRepo:    userRepo,
+		authService: authService,
+		config:      config,
+	}
+}
+
+// AuthorizationURL starts a login and returns the provider URL to redirect the user to
+func (s *oidcService) AuthorizationURL(ctx context.Context) (string, error) {
+	state, err := security.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := security.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	codeVerifier, err := security.RandomToken(48)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if err := s.oidcRepo.DeleteExpiredLoginStates(now); err != nil {
+		log.Printf("Failed to delete expired OIDC login states: %v", err)
+	}
+
+	loginState := &model.OIDCLoginState{
+		StateHash:    security.HashToken(state),
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    now.Add(time.Duration(s.config.OIDC.LoginStateMinutes) * time.Minute),
+	}
+	if err := s.oidcRepo.CreateLoginState(loginState); err != nil {
+		return "", err
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		log.Printf("Failed to build OIDC authorization URL: %v", err)
+		return "", errors.New("single sign-on failed")
+	}
+
+	return authURL, nil
+}
+
+// CompleteLogin redeems the authorization code, links or creates the user and issues tokens.
+// The identity provider only counts as the first factor, so users with two-factor
+// authentication get an MFA challenge instead.
+func (s *oidcService) CompleteLogin(ctx context.Context, req *model.OIDCCallbackRequest, client model.ClientInfo) (*model.LoginResponse, *model.MFAChallenge, error) {
+	loginState, err := s.oidcRepo.ConsumeLoginState(security.HashToken(req.State))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("invalid or expired login state")
+		}
+		return nil, nil, err
+	}
+	if time.Now().After(loginState.ExpiresAt) {
+		return nil, nil, errors.New("invalid or expired login state")
+	}
+
+	idToken, err := s.provider.Exchange(ctx, req.Code, loginState.CodeVerifier)
+	if err != nil {
+		log.Printf("OIDC code exchange failed: %v", err)
+		return nil, nil, errors.New("single sign-on failed")
+	}
+
+	claims, err := s.provider.VerifyIDToken(ctx, idToken, loginState.Nonce)
+	if err != nil {
+		log.Printf("OIDC ID token verification failed: %v", err)
+		return nil, nil, errors.New("single sign-on failed")
+	}
+
+	user, err := s.resolveUser(claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.authService.ContinueLogin(user, client, false)
+}
+
+// resolveUser finds the user linked to the external identity, linking or creating one by verified email
+func (s *oidcService) resolveUser(claims *oidc.Claims) (*model.User, error) {
+	issuer := s.config.OIDC.Issuer
+
+	identity, err := s.oidcRepo.GetIdentity(issuer, claims.Subject)
+	if err == nil {
+		return s.userRepo.GetByID(identity.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, errors.New("email not verified by identity provider")
+	}
+
+	user, err := s.userRepo.GetByEmail(claims.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if user != nil {
+		// Someone else may have registered the address without owning it
+		if !user.IsEmailVerified() {
+			return nil, errors.New("account email not verified")
+		}
+	} else {
+		if !s.config.OIDC.AllowSignup {
+			return nil, errors.New("signup via single sign-on is disabled")
+		}
+		if user, err = s.createUser(claims); err != nil {
+			return nil, err
+		}
+	}
+
+	identity = &model.ExternalIdentity{
+		UserID:  user.ID,
+		Issuer:  issuer,
+		Subject: claims.Subject,
+		Email:   claims.Email,
+	}
+	if err := s.oidcRepo.CreateIdentity(identity); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createUser creates an account without a usable password for an external identity
+func (s *oidcService) createUser(claims *oidc.Claims) (*model.User, error) {
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	verifiedAt := time.Now()
+	user := &model.User{
+		Name:            name,
+		Email:           claims.Email,
+		EmailVerifiedAt: &verifiedAt,
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/oidc/oidctest"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// fakeOIDCRepo keeps login states and identities in memory
+type fakeOIDCRepo struct {
+	states     map[string]*model.OIDCLoginState
+	identities []model.ExternalIdentity
+}
+
+func (r *fakeOIDCRepo) CreateLoginState(state *model.OIDCLoginState) error {
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *fakeOIDCRepo) ConsumeLoginState(stateHash string) (*model.OIDCLoginState, error) {
+	state, ok := r.states[stateHash]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delete(r.states, stateHash)
+	return state, nil
+}
+
+func (r *fakeOIDCRepo) DeleteExpiredLoginStates(now time.Time) error {
+	return nil
+}
+
+func (r *fakeOIDCRepo) GetIdentity(issuer, subject string) (*model.ExternalIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeOIDCRepo) CreateIdentity(identity *model.ExternalIdentity) error {
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+// fakeUserRepo keeps users in memory. Methods the OIDC service does not use panic.
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[string]*model.User
+}
+
+func (r *fakeUserRepo) Create(user *model.User) error {
+	user.ID = "user-" + strconv.Itoa(len(r.users)+1)
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepo) GetByID(id string) (*model.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) GetByEmail(email string) (*model.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeAuthService records how a login is continued. Issuing tokens directly panics.
+type fakeAuthService struct {
+	AuthService
+	continued   *model.User
+	multiFactor bool
+}
+
+func (s *fakeAuthService) ContinueLogin(user *model.User, client model.ClientInfo, multiFactor bool) (*model.LoginResponse, *model.MFAChallenge, error) {
+	s.continued = user
+	s.multiFactor = multiFactor
+	if user.IsTOTPEnabled() && !multiFactor {
+		return nil, &model.MFAChallenge{MFARequired: true, MFAToken: "mfa-token"}, nil
+	}
+	return &model.LoginResponse{Token: "access-token", User: user.ToResponse()}, nil, nil
+}
+
+type oidcTest struct {
+	idp      *oidctest.Server
+	service  OIDCService
+	oidcRepo *fakeOIDCRepo
+	userRepo *fakeUserRepo
+	auth     *fakeAuthService
+}
+
+func newOIDCTest(t *testing.T) *oidcTest {
+	t.Helper()
+	idp := oidctest.NewServer("todo-app", "")
+	t.Cleanup(idp.Close)
+
+	cfg := &config.Config{OIDC: config.OIDCConfig{
+		Enabled:           true,
+		Issuer:            idp.Issuer(),
+		ClientID:          "todo-app",
+		RedirectURL:       "https://todo.example.com/auth/oidc/callback",
+		Scopes:            []string{"openid", "email"},
+		AllowSignup:       true,
+		LoginStateMinutes: 10,
+	}}
+
+	test := &oidcTest{
+		idp:      idp,
+		oidcRepo: &fakeOIDCRepo{states: make(map[string]*model.OIDCLoginState)},
+		userRepo: &fakeUserRepo{users: make(map[string]*model.User)},
+		auth:     &fakeAuthService{},
+	}
+	test.service = NewOIDCService(test.oidcRepo, test.userRepo, test.auth, cfg)
+	return test
+}
+
+// authorize starts a login and signs in at the provider
+func (o *oidcTest) authorize(t *testing.T, identity oidctest.Identity) *model.OIDCCallbackRequest {
+	t.Helper()
+	authURL, err := o.service.AuthorizationURL(context.Background())
+	if err != nil {
+		t.Fatalf("AuthorizationURL() error = %v", err)
+	}
+	code, state, err := o.idp.Authorize(authURL, identity)
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	return &model.OIDCCallbackRequest{Code: code, State: state}
+}
+
+var aliceIdentity = oidctest.Identity{
+	Subject:       "alice-subject",
+	Email:         "alice@example.com",
+	EmailVerified: true,
+	Name:          "Alice",
+}
+
+func TestOIDCCompleteLoginSignsUp(t *testing.T) {
+	o := newOIDCTest(t)
+
+	response, challenge, err := o.service.CompleteLogin(context.Background(), o.authorize(t, aliceIdentity), model.ClientInfo{})
+	if err != nil {
+		t.Fatalf("CompleteLogin() error = %v", err)
+	}
+	if challenge != nil || response == nil {
+		t.Fatalf("CompleteLogin() = %v, %v, want a login response", response, challenge)
+	}
+	if response.User.Email != "alice@example.com" || response.User.Name != "Alice" {
+		t.Errorf("user = %+v, want alice@example.com", response.User)
+	}
+	if len(o.oidcRepo.identities) != 1 || o.oidcRepo.identities[0].Subject != "alice-subject" {
+		t.Errorf("identities = %+v, want one linked identity", o.oidcRepo.identities)
+	}
+}
+
+func TestOIDCCompleteLoginRequiresSecondFactor(t *testing.T) {
+	o := newOIDCTest(t)
+	enabledAt := time.Now()
+	verifiedAt := time.Now()
+	o.userRepo.users["user-1"] = &model.User{
+		ID:              "user-1",
+		Email:           "alice@example.com",
+		EmailVerifiedAt: &verifiedAt,
+		TOTPEnabledAt:   &enabledAt,
+	}
+
+	response, challenge, err := o.service.CompleteLogin(context.Background(), o.authorize(t, aliceIdentity), model.ClientInfo{})
+	if err != nil {
+		t.Fatalf("CompleteLogin() error = %v", err)
+	}
+	if response != nil || challenge == nil || !challenge.MFARequired {
+		t.Errorf("CompleteLogin() = %v, %v, want an MFA challenge", response, challenge)
+	}
+	if o.auth.continued == nil || o.auth.continued.ID != "user-1" || o.auth.multiFactor {
+		t.Errorf("ContinueLogin(%v, multiFactor=%v), want user-1 with a single factor", o.auth.continued, o.auth.multiFactor)
+	}
+}
+
+func TestOIDCCompleteLoginRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, o *oidcTest) *model.OIDCCallbackRequest
+		wantErr string
+	}{
+		{
+			name: "unknown state",
+			prepare: func(t *testing.T, o *oidcTest) *model.OIDCCallbackRequest {
+				req := o.authorize(t, aliceIdentity)
+				req.State = "forged-state"
+				return req
+			},
+			wantErr: "invalid or expired login state",
+		},
+		{
+			name: "expired state",
+			prepare: func(t *testing.T, o *oidcTest) *model.OIDCCallbackRequest {
+				req := o.authorize(t, aliceIdentity)
+				for _, state := range o.oidcRepo.states {
+					state.ExpiresAt = time.Now().Add(-time.Second)
+				}
+				return req
+			},
+			wantErr: "invalid or expired login state",
+		},
+		{
+			name: "state used twice",
+			prepare: func(t *testing.T, o *oidcTest) *model.OIDCCallbackRequest {
+				req := o.authorize(t, aliceIdentity)
+				if _, _, err := o.service.CompleteLogin(context.Background(), req, model.ClientInfo{}); err != nil {
+					t.Fatalf("first CompleteLogin() error = %v", err)
+				}
+				return req
+			},
+			wantErr: "invalid or expired login state",
+		},
+		{
+			name: "code of another login",
+			prepare: func(t *testing.T, o *oidcTest) *model.OIDCCallbackRequest {
+				// The code is bound to the PKCE challenge of the login that obtained it
+				first := o.authorize(t, aliceIdentity)
+				second := o.authorize(t, aliceIdentity)
+				return &model.OIDCCallbackRequest{Code: first.Code, State: second.State}
+			},
+			wantErr: "single sign-on failed",
+		},
+		{
+			name: "nonce mismatch",
+			prepare: func(t *testing.T, o *oidcTest) *model.OIDCCallbackRequest {
+				o.idp.Claims = func(claims jwt.MapClaims) { claims["nonce"] = "replayed-nonce" }
+				return o.authorize(t, aliceIdentity)
+			},
+			wantErr: "single sign-on failed",
+		},
+		{
+			name: "wrong audience",
+			prepare: func(t *testing.T, o *oidcTest) *model.OIDCCallbackRequest {
+				o.idp.Claims = func(claims jwt.MapClaims) { claims["aud"] = "other-app" }
+				return o.authorize(t, aliceIdentity)
+			},
+			wantErr: "single sign-on failed",
+		},
+		{
+			name: "email not verified by the provider",
+			prepare: func(t *testing.T, o *oidcTest) *model.OIDCCallbackRequest {
+				identity := aliceIdentity
+				identity.EmailVerified = false
+				return o.authorize(t, identity)
+			},
+			wantErr: "email not verified by identity provider",
+		},
+		{
+			name: "existing account with unverified email",
+			prepare: func(t *testing.T, o *oidcTest) *model.OIDCCallbackRequest {
+				o.userRepo.users["user-1"] = &model.User{ID: "user-1", Email: "alice@example.com"}
+				return o.authorize(t, aliceIdentity)
+			},
+			wantErr: "account email not verified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOIDCTest(t)
+			req := tt.prepare(t, o)
+			o.auth.continued = nil
+
+			response, challenge, err := o.service.CompleteLogin(context.Background(), req, model.ClientInfo{})
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("CompleteLogin() error = %v, want %q", err, tt.wantErr)
+			}
+			if response != nil || challenge != nil || o.auth.continued != nil {
+				t.Errorf("CompleteLogin() logged in after an error")
+			}
+		})
+	}
+}
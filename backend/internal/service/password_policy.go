@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+)
+
+// Password policy violation codes
+const (
+	PasswordTooShort      = "too_short"
+	PasswordTooLong       = "too_long"
+	PasswordTooWeak       = "too_weak"
+	PasswordPersonalInfo  = "contains_personal_info"
+	PasswordKnownBreached = "breached"
+)
+
+// minPersonalTokenLength is the shortest part of a name or email that a password may not contain
+const minPersonalTokenLength = 3
+
+// PasswordPolicy defines the interface for checking new passwords
+type PasswordPolicy interface {
+	Validate(password, email, name string) error
+}
+
+// passwordPolicy implements PasswordPolicy interface
+type passwordPolicy struct {
+	breached security.BreachedPasswords
+	config   *config.Config
+}
+
+// NewPasswordPolicy creates a new password policy. breached may be nil to skip the breach check.
+func NewPasswordPolicy(breached security.BreachedPasswords, config *config.Config) PasswordPolicy {
+	return &passwordPolicy{
+		breached: breached,
+		config:   config,
+	}
+}
+
+// Validate checks a password chosen by the user with the given email and name.
+// It returns a *PasswordPolicyError listing every violation.
+func (p *passwordPolicy) Validate(password, email, name string) error {
+	var violations []PasswordViolation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.config.Auth.PasswordMinLength {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordTooShort,
+			Message: fmt.Sprintf("Password must be at least %d characters long", p.config.Auth.PasswordMinLength),
+		})
+	}
+	if p.config.Auth.PasswordMaxLength > 0 && length > p.config.Auth.PasswordMaxLength {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordTooLong,
+			Message: fmt.Sprintf("Password must be at most %d characters long", p.config.Auth.PasswordMaxLength),
+		})
+	}
+
+	if p.config.Auth.PasswordRejectPersonalInfo && containsPersonalInfo(password, email, name) {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordPersonalInfo,
+			Message: "Password must not contain your name or email address",
+		})
+	}
+
+	if security.PasswordStrength(password, email, name) < p.config.Auth.PasswordMinScore {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordTooWeak,
+			Message: "Password is too easy to guess; avoid common words, sequences and repeated characters",
+		})
+	}
+
+	if p.breached != nil {
+		breached, err := p.breached.Contains(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			violations = append(violations, PasswordViolation{
+				Code:    PasswordKnownBreached,
+				Message: "Password has appeared in a data breach; choose a different one",
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// containsPersonalInfo reports whether a password contains the email address,
+// its local part or a part of the name
+func containsPersonalInfo(password, email, name string) bool {
+	lower := strings.ToLower(password)
+	email = strings.ToLower(email)
+	localPart, _, _ := strings.Cut(email, "@")
+
+	tokens := []string{email, localPart}
+	tokens = append(tokens, strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})...)
+
+	for _, token := range tokens {
+		if utf8.RuneCountInString(token) >= minPersonalTokenLength && strings.Contains(lower, token) {
+			return true
+		}
+	}
+	return false
+}
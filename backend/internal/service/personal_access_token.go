@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+	"gorm.io/gorm"
+)
+
+// lastUsedResolution limits how often token usage is written to the database
+const lastUsedResolution = time.Minute
+
+// TokenService defines the interface for personal access token operations
+type TokenService interface {
+	Create(userID string, req *model.CreatePersonalAccessTokenRequest) (*model.CreatePersonalAccessTokenResponse, error)
+	List(userID string) ([]model.PersonalAccessTokenResponse, error)
+	Revoke(userID, tokenID string) error
+	Authenticate(tokenString string) (*model.User, []string, error)
+}
+
+// tokenService implements TokenService interface
+type tokenService struct {
+	tokenRepo repository.PersonalAccessTokenRepository
+	userRepo  repository.UserRepository
+}
+
+// NewTokenService creates a new personal access token service
+func NewTokenService(tokenRepo repository.PersonalAccessTokenRepository, userRepo repository.UserRepository) TokenService {
+	return &tokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// Create creates a new personal access token and returns it in plain text once
+func (s *tokenService) Create(userID string, req *model.CreatePersonalAccessTokenRequest) (*model.CreatePersonalAccessTokenResponse, error) {
+	secret, err := security.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	plain := model.PersonalAccessTokenPrefix + secret
+
+	token := &model.PersonalAccessToken{
+		UserID:    userID,
+		Name:      req.Name,
+		TokenHash: security.HashToken(plain),
+		Prefix:    plain[:len(model.PersonalAccessTokenPrefix)+6],
+		Scopes:    strings.Join(uniqueStrings(req.Scopes), " "),
+	}
+
+	if req.ExpiresInDays != nil {
+		expiresAt := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, err
+	}
+
+	response := &model.CreatePersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: token.ToResponse(),
+		Token:                       plain,
+	}
+
+	return response, nil
+}
+
+// List retrieves the active personal access tokens of a user
+func (s *tokenService) List(userID string) ([]model.PersonalAccessTokenResponse, error) {
+	tokens, err := s.tokenRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.PersonalAccessTokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = token.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// Revoke revokes a personal access token of a user
+func (s *tokenService) Revoke(userID, tokenID string) error {
+	revoked, err := s.tokenRepo.Revoke(userID, tokenID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("token not found")
+	}
+	return nil
+}
+
+// Authenticate resolves a personal access token to its user and granted scopes
+func (s *tokenService) Authenticate(tokenString string) (*model.User, []string, error) {
+	token, err := s.tokenRepo.GetByHash(security.HashToken(tokenString))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("invalid token")
+		}
+		return nil, nil, err
+	}
+
+	if !token.IsUsable() {
+		return nil, nil, errors.New("invalid token")
+	}
+
+	user, err := s.userRepo.GetByID(token.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("invalid token")
+		}
+		return nil, nil, err
+	}
+	if user.IsDisabled() {
+		return nil, nil, errors.New("invalid token")
+	}
+
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > lastUsedResolution {
+		if err := s.tokenRepo.UpdateLastUsed(token.ID, now); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return user, token.ScopeList(), nil
+}
+
+// uniqueStrings returns values without duplicates, keeping their order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
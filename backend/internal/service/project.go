@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ProjectService defines the interface for project operations
+type ProjectService interface {
+	List(userID string, req *model.ProjectListRequest) ([]model.Project, error)
+	GetByID(userID, projectID string) (*model.Project, error)
+	Create(userID string, req *model.CreateProjectRequest) (*model.Project, error)
+	Update(userID, projectID string, req *model.UpdateProjectRequest) (*model.Project, error)
+	Delete(userID, projectID string) error
+}
+
+// projectService implements ProjectService interface
+type projectService struct {
+	projectRepo repository.ProjectRepository
+}
+
+// NewProjectService creates a new project service
+func NewProjectService(projectRepo repository.ProjectRepository) ProjectService {
+	return &projectService{
+		projectRepo: projectRepo,
+	}
+}
+
+// List retrieves the projects of a user in display order
+func (s *projectService) List(userID string, req *model.ProjectListRequest) ([]model.Project, error) {
+	return s.projectRepo.GetByUserID(userID, req)
+}
+
+// GetByID retrieves a project by ID for a specific user
+func (s *projectService) GetByID(userID, projectID string) (*model.Project, error) {
+	project, err := s.projectRepo.GetUserProjectByID(userID, projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("project not found")
+		}
+		return nil, err
+	}
+	return project, nil
+}
+
+// Create creates a new project after the user's existing projects
+func (s *projectService) Create(userID string, req *model.CreateProjectRequest) (*model.Project, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("project name is required")
+	}
+
+	color := strings.ToLower(req.Color)
+	if color == "" {
+		color = model.DefaultProjectColor
+	}
+
+	position, err := s.projectRepo.NextPosition(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &model.Project{
+		UserID:      userID,
+		Name:        name,
+		Description: req.Description,
+		Color:       color,
+		Position:    position,
+	}
+
+	if err := s.projectRepo.Create(project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// Update updates a project. Archiving hides its todos from default todo listings.
+func (s *projectService) Update(userID, projectID string, req *model.UpdateProjectRequest) (*model.Project, error) {
+	project, err := s.GetByID(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("project name is required")
+		}
+		project.Name = name
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+	if req.Color != nil {
+		project.Color = strings.ToLower(*req.Color)
+	}
+	if req.Archived != nil {
+		project.Archived = *req.Archived
+	}
+	if req.Position != nil {
+		project.Position = *req.Position
+	}
+
+	if err := s.projectRepo.Update(project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// Delete deletes a project. Its todos are kept without a project.
+func (s *projectService) Delete(userID, projectID string) error {
+	if _, err := s.GetByID(userID, projectID); err != nil {
+		return err
+	}
+
+	return s.projectRepo.Delete(projectID)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ProxyAuthService defines the interface for users authenticated by a trusted reverse proxy
+type ProxyAuthService interface {
+	Authenticate(username, email string) (*model.User, error)
+}
+
+// proxyAuthService implements ProxyAuthService interface
+type proxyAuthService struct {
+	userRepo repository.UserRepository
+	config   *config.Config
+}
+
+// NewProxyAuthService creates a new reverse proxy authentication service
+func NewProxyAuthService(userRepo repository.UserRepository, config *config.Config) ProxyAuthService {
+	return &proxyAuthService{
+		userRepo: userRepo,
+		config:   config,
+	}
+}
+
+// Authenticate resolves the identity asserted by the proxy to a user, creating the
+// account on first sight when auto-provisioning is enabled. Without an email header
+// the email is built from the username and the configured email domain. Existing
+// accounts are only used once their email is verified.
+func (s *proxyAuthService) Authenticate(username, email string) (*model.User, error) {
+	username = strings.TrimSpace(username)
+	email = strings.TrimSpace(email)
+	if email == "" {
+		switch {
+		case strings.Contains(username, "@"):
+			email = username
+		case username != "" && s.config.ProxyAuth.EmailDomain != "":
+			email = username + "@" + s.config.ProxyAuth.EmailDomain
+		}
+	}
+
+	if !isEmailAddress(email) {
+		return nil, errors.New("invalid proxy identity")
+	}
+
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if !s.config.ProxyAuth.AutoProvision {
+			return nil, errors.New("user not provisioned")
+		}
+		if user, err = s.provision(username, email); err != nil {
+			return nil, err
+		}
+	}
+
+	if user.IsDisabled() {
+		return nil, errors.New("account disabled")
+	}
+
+	// Someone else may have registered the address without owning it
+	if !user.IsEmailVerified() {
+		return nil, errors.New("account email not verified")
+	}
+
+	return user, nil
+}
+
+// provision creates an account without a usable password for a proxy identity
+func (s *proxyAuthService) provision(username, email string) (*model.User, error) {
+	name := username
+	if name == "" || strings.Contains(name, "@") {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	// The proxy has already authenticated the address
+	verifiedAt := time.Now()
+	user := &model.User{
+		Name:            name,
+		Email:           email,
+		EmailVerifiedAt: &verifiedAt,
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		// A concurrent first request may have created the account already
+		if existing, getErr := s.userRepo.GetByEmail(email); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
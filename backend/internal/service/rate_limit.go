@@ -0,0 +1,50 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+)
+
+// RateLimiter defines the interface for limiting how often an action may be performed per key
+type RateLimiter interface {
+	Allow(key string) error
+}
+
+// rateLimiter implements RateLimiter with fixed windows kept in the login attempt store,
+// so limits are shared between replicas when the postgres store is used
+type rateLimiter struct {
+	store  repository.LoginAttemptStore
+	prefix string
+	limit  int
+	window time.Duration
+}
+
+// NewRateLimiter creates a rate limiter that allows limit actions per key and window.
+// The prefix keeps its counters apart from others in the same store.
+func NewRateLimiter(store repository.LoginAttemptStore, prefix string, limit int, window time.Duration) RateLimiter {
+	return &rateLimiter{
+		store:  store,
+		prefix: prefix,
+		limit:  limit,
+		window: window,
+	}
+}
+
+// Allow counts an action for a key and returns a *TooManyRequestsError once the limit is exceeded
+func (l *rateLimiter) Allow(key string) error {
+	attempt, err := l.store.RecordFailure(l.prefix+":"+strings.ToLower(strings.TrimSpace(key)), l.window)
+	if err != nil {
+		return err
+	}
+
+	if attempt.Failures > l.limit {
+		return &TooManyRequestsError{
+			Message:    "too many requests",
+			RetryAfter: time.Until(attempt.WindowStart.Add(l.window)),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/notifier"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ReminderService defines the interface for due date reminder operations
+type ReminderService interface {
+	List(userID, todoID string) ([]model.Reminder, error)
+	Create(userID, todoID string, req *model.CreateReminderRequest) (*model.Reminder, error)
+	Delete(userID, todoID, reminderID string) error
+	DeliverDue() (int, error)
+	StartScheduler()
+}
+
+// reminderService implements ReminderService interface
+type reminderService struct {
+	reminderRepo repository.ReminderRepository
+	todoService  TodoService
+	notifier     notifier.Notifier
+	config       *config.Config
+}
+
+// NewReminderService creates a new reminder service
+func NewReminderService(reminderRepo repository.ReminderRepository, todoService TodoService, notifier notifier.Notifier, config *config.Config) ReminderService {
+	return &reminderService{
+		reminderRepo: reminderRepo,
+		todoService:  todoService,
+		notifier:     notifier,
+		config:       config,
+	}
+}
+
+// List retrieves the reminders of a todo in the order they fire
+func (s *reminderService) List(userID, todoID string) ([]model.Reminder, error) {
+	todo, err := s.todoService.GetByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	return todo.Reminders, nil
+}
+
+// Create adds a reminder to a todo. A todo without a due date keeps the reminder
+// until a due date is set.
+func (s *reminderService) Create(userID, todoID string, req *model.CreateReminderRequest) (*model.Reminder, error) {
+	todo, err := s.todoService.GetByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(todo.Reminders) >= model.MaxTodoReminders {
+		return nil, errors.New("too many reminders")
+	}
+
+	exists, err := s.reminderRepo.OffsetExists(todoID, *req.OffsetMinutes)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("reminder already exists")
+	}
+
+	reminder := &model.Reminder{
+		TodoID:        todoID,
+		UserID:        userID,
+		OffsetMinutes: *req.OffsetMinutes,
+	}
+	reminder.Schedule(todo.DueDate)
+
+	if err := s.reminderRepo.Create(reminder); err != nil {
+		// A concurrent request may have added the same offset
+		if exists, existsErr := s.reminderRepo.OffsetExists(todoID, *req.OffsetMinutes); existsErr == nil && exists {
+			return nil, errors.New("reminder already exists")
+		}
+		return nil, err
+	}
+
+	return reminder, nil
+}
+
+// Delete deletes a reminder of a todo
+func (s *reminderService) Delete(userID, todoID, reminderID string) error {
+	if _, err := s.todoService.GetByID(userID, todoID); err != nil {
+		return err
+	}
+
+	if _, err := s.reminderRepo.GetTodoReminderByID(todoID, reminderID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("reminder not found")
+		}
+		return err
+	}
+
+	return s.reminderRepo.Delete(reminderID)
+}
+
+// DeliverDue fires every reminder that is due, one batch at a time, and returns how many fired.
+// Failed deliveries are retried with a growing delay until they run out of attempts.
+func (s *reminderService) DeliverDue() (int, error) {
+	batchSize := s.config.Reminders.BatchSize
+	if batchSize < 1 {
+		batchSize = 50
+	}
+	claim := time.Duration(s.config.Reminders.ClaimSeconds) * time.Second
+	if claim <= 0 {
+		claim = 10 * time.Minute
+	}
+	maxAttempts := max(s.config.Reminders.MaxAttempts, 1)
+
+	total := 0
+	for {
+		now := time.Now()
+		due, err := s.reminderRepo.ClaimDue(now, now.Add(claim), maxAttempts, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for i := range due {
+			reminder := &due[i].Reminder
+			if err := s.deliver(reminder, &due[i].Todo); err != nil {
+				if reminder.Attempts >= maxAttempts {
+					log.Printf("Giving up on reminder %s after %d attempts", reminder.ID, reminder.Attempts)
+				}
+				if err := s.reminderRepo.MarkFailed(reminder.ID, err.Error(), time.Now().Add(reminderRetryDelay(reminder.Attempts))); err != nil {
+					log.Printf("Failed to record reminder error: %v", err)
+				}
+				continue
+			}
+			if err := s.reminderRepo.MarkSent(reminder, time.Now()); err != nil {
+				log.Printf("Failed to mark reminder %s as sent: %v", reminder.ID, err)
+			}
+		}
+		total += len(due)
+
+		if len(due) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// StartScheduler delivers due reminders in the background. It is safe to run on every
+// replica because each reminder is claimed by one of them before it is delivered.
+// A reminder is delivered again if its claim runs out before it is marked sent.
+func (s *reminderService) StartScheduler() {
+	if !s.config.Reminders.Enabled {
+		return
+	}
+
+	interval := time.Duration(s.config.Reminders.PollSeconds) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	deliver := func() {
+		if _, err := s.DeliverDue(); err != nil {
+			log.Printf("Failed to deliver reminders: %v", err)
+		}
+	}
+
+	go func() {
+		deliver()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			deliver()
+		}
+	}()
+}
+
+// deliver sends a claimed reminder through the notifier. Reminders of deleted or
+// disabled accounts are dropped. When one of several channels fails the reminder is
+// retried on all of them, so channels have to tolerate duplicates.
+func (s *reminderService) deliver(reminder *model.Reminder, todo *model.Todo) error {
+	if todo.User.ID == "" || todo.User.IsDisabled() || todo.DueDate == nil {
+		return nil
+	}
+
+	err := s.notifier.Notify(&notifier.Notification{
+		ReminderID: reminder.ID,
+		TodoID:     todo.ID,
+		Title:      todo.Title,
+		DueDate:    *todo.DueDate,
+		RemindAt:   *reminder.RemindAt,
+		UserID:     todo.UserID,
+		Email:      todo.User.Email,
+		Name:       todo.User.Name,
+	})
+	if err != nil {
+		log.Printf("Failed to deliver reminder %s: %v", reminder.ID, err)
+	}
+	return err
+}
+
+// reminderRetryDelay returns how long to wait before retrying a reminder after a failed
+// attempt: one minute after the first, doubling up to an hour
+func reminderRetryDelay(attempts int) time.Duration {
+	delay := time.Minute
+	for i := 1; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	return min(delay, time.Hour)
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/notifier"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+)
+
+// fakeReminderRepo hands out one due reminder per claim until it has been sent
+type fakeReminderRepo struct {
+	repository.ReminderRepository
+	reminder model.Reminder
+	todo     model.Todo
+	retryAt  time.Time
+}
+
+func (r *fakeReminderRepo) ClaimDue(now, claimedUntil time.Time, maxAttempts, limit int) ([]model.DueReminder, error) {
+	if r.reminder.SentAt != nil || r.reminder.Attempts >= maxAttempts || now.Before(r.retryAt) {
+		return nil, nil
+	}
+	r.reminder.Attempts++
+	r.reminder.ClaimedUntil = &claimedUntil
+	return []model.DueReminder{{Reminder: r.reminder, Todo: r.todo}}, nil
+}
+
+func (r *fakeReminderRepo) MarkSent(reminder *model.Reminder, sentAt time.Time) error {
+	r.reminder.SentAt = &sentAt
+	return nil
+}
+
+func (r *fakeReminderRepo) MarkFailed(id, lastError string, retryAt time.Time) error {
+	r.reminder.LastError = lastError
+	r.retryAt = retryAt
+	return nil
+}
+
+// fakeNotifier fails the first failures notifications
+type fakeNotifier struct {
+	failures int
+	sent     []string
+}
+
+func (n *fakeNotifier) Notify(notification *notifier.Notification) error {
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("webhook: unexpected status 503")
+	}
+	n.sent = append(n.sent, notification.ReminderID)
+	return nil
+}
+
+func TestDeliverDueRetries(t *testing.T) {
+	dueDate := time.Now().Add(-time.Minute)
+	repo := &fakeReminderRepo{
+		reminder: model.Reminder{ID: "reminder-1", TodoID: "todo-1", RemindAt: &dueDate},
+		todo:     model.Todo{ID: "todo-1", UserID: "user-1", DueDate: &dueDate, User: model.User{ID: "user-1"}},
+	}
+	notify := &fakeNotifier{failures: 1}
+	cfg := &config.Config{Reminders: config.RemindersConfig{BatchSize: 10, MaxAttempts: 3}}
+	service := NewReminderService(repo, nil, notify, cfg)
+
+	if _, err := service.DeliverDue(); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	if repo.reminder.SentAt != nil || repo.reminder.LastError == "" {
+		t.Fatalf("reminder = %+v, want a failed attempt that is not sent", repo.reminder)
+	}
+	if delay := time.Until(repo.retryAt); delay < 50*time.Second || delay > time.Minute {
+		t.Errorf("retry in %v, want about a minute", delay)
+	}
+
+	// The retry is due once its delay has passed
+	repo.retryAt = time.Now()
+	if _, err := service.DeliverDue(); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	if repo.reminder.SentAt == nil || len(notify.sent) != 1 {
+		t.Errorf("reminder sent at %v with %d notifications, want one delivery", repo.reminder.SentAt, len(notify.sent))
+	}
+}
+
+func TestDeliverDueGivesUp(t *testing.T) {
+	dueDate := time.Now().Add(-time.Minute)
+	repo := &fakeReminderRepo{
+		reminder: model.Reminder{ID: "reminder-1", TodoID: "todo-1", RemindAt: &dueDate},
+		todo:     model.Todo{ID: "todo-1", UserID: "user-1", DueDate: &dueDate, User: model.User{ID: "user-1"}},
+	}
+	notify := &fakeNotifier{failures: 10}
+	cfg := &config.Config{Reminders: config.RemindersConfig{BatchSize: 10, MaxAttempts: 3}}
+	service := NewReminderService(repo, nil, notify, cfg)
+
+	for range 5 {
+		repo.retryAt = time.Now()
+		if _, err := service.DeliverDue(); err != nil {
+			t.Fatalf("DeliverDue() error = %v", err)
+		}
+	}
+	if repo.reminder.Attempts != 3 || repo.reminder.SentAt != nil {
+		t.Errorf("reminder = %+v, want three failed attempts", repo.reminder)
+	}
+}
+
+func TestReminderRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{10, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := reminderRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("reminderRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// scimMaxResults caps the page size of SCIM list responses
+const scimMaxResults = 100
+
+// scimFilterPattern matches the only filter form supported: attribute eq "value"
+var scimFilterPattern = regexp.MustCompile(`(?i)^\s*([a-z][\w:.]*)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// SCIMService defines the interface for SCIM 2.0 user provisioning (RFC 7644)
+type SCIMService interface {
+	CreateUser(req *model.SCIMUser) (*model.User, error)
+	GetUser(userID string) (*model.User, error)
+	ListUsers(req *model.SCIMListRequest) (*model.SCIMListResponse, error)
+	PatchUser(userID string, req *model.SCIMPatchRequest) (*model.User, error)
+	DeprovisionUser(userID string) error
+}
+
+// scimService implements SCIMService interface
+type scimService struct {
+	userRepo    repository.UserRepository
+	authService AuthService
+}
+
+// NewSCIMService creates a new SCIM provisioning service
+func NewSCIMService(userRepo repository.UserRepository, authService AuthService) SCIMService {
+	return &scimService{
+		userRepo:    userRepo,
+		authService: authService,
+	}
+}
+
+// CreateUser provisions an account without a usable password; users sign in through single sign-on
+func (s *scimService) CreateUser(req *model.SCIMUser) (*model.User, error) {
+	email := strings.TrimSpace(req.UserName)
+	if !isEmailAddress(email) {
+		return nil, errors.New("invalid user")
+	}
+
+	exists, err := s.userRepo.EmailExists(email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("user already exists")
+	}
+
+	patch := scimUserPatch{displayName: &req.DisplayName}
+	if req.Name != nil {
+		patch.formatted = &req.Name.Formatted
+		patch.givenName = &req.Name.GivenName
+		patch.familyName = &req.Name.FamilyName
+	}
+	name := patch.name()
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	// The directory vouches for the address
+	now := time.Now()
+	user := &model.User{
+		Name:            name,
+		Email:           email,
+		EmailVerifiedAt: &now,
+	}
+	if req.Active != nil && !*req.Active {
+		user.DisabledAt = &now
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		// A concurrent request may have taken the address
+		if exists, existsErr := s.userRepo.EmailExists(email); existsErr == nil && exists {
+			return nil, errors.New("user already exists")
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUser retrieves a user by ID
+func (s *scimService) GetUser(userID string) (*model.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// ListUsers retrieves a page of users, optionally filtered by userName or email
+func (s *scimService) ListUsers(req *model.SCIMListRequest) (*model.SCIMListResponse, error) {
+	// Set default values
+	if req.StartIndex < 1 {
+		req.StartIndex = 1
+	}
+	count := scimMaxResults
+	if req.Count != nil && *req.Count < scimMaxResults {
+		count = *req.Count
+	}
+
+	var users []model.User
+	var total int64
+	if req.Filter != "" {
+		email, err := parseSCIMFilter(req.Filter)
+		if err != nil {
+			return nil, err
+		}
+
+		user, err := s.userRepo.GetByEmail(email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if user != nil {
+			total = 1
+			if req.StartIndex == 1 && count > 0 {
+				users = append(users, *user)
+			}
+		}
+	} else {
+		var err error
+		users, total, err = s.userRepo.ListRange(req.StartIndex-1, count)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resources := make([]model.SCIMUser, len(users))
+	for i, user := range users {
+		resources[i] = user.ToSCIM()
+	}
+
+	return &model.SCIMListResponse{
+		Schemas:      []string{model.SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   req.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+// PatchUser applies add and replace operations to a user. Attributes that are not
+// stored, such as externalId, are ignored. Deactivating a user revokes their tokens.
+func (s *scimService) PatchUser(userID string, req *model.SCIMPatchRequest) (*model.User, error) {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch scimUserPatch
+	for _, op := range req.Operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+		default:
+			return nil, errors.New("invalid patch operation")
+		}
+		if err := patch.apply(op.Path, op.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.userName != nil {
+		email := strings.TrimSpace(*patch.userName)
+		if !isEmailAddress(email) {
+			return nil, errors.New("invalid user")
+		}
+		if email != user.Email {
+			exists, err := s.userRepo.EmailExists(email)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, errors.New("user already exists")
+			}
+			now := time.Now()
+			user.Email = email
+			user.EmailVerifiedAt = &now
+		}
+	}
+
+	if name := patch.name(); name != "" {
+		if len([]rune(name)) > 100 {
+			return nil, errors.New("invalid user")
+		}
+		user.Name = name
+	}
+
+	deactivated := false
+	if patch.active != nil {
+		if *patch.active {
+			user.DisabledAt = nil
+		} else if !user.IsDisabled() {
+			now := time.Now()
+			user.DisabledAt = &now
+			deactivated = true
+		}
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	if deactivated {
+		if err := s.authService.RevokeAllTokens(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// DeprovisionUser disables a user and revokes all of their tokens. The account is
+// kept so the directory can reactivate it.
+func (s *scimService) DeprovisionUser(userID string) error {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsDisabled() {
+		now := time.Now()
+		user.DisabledAt = &now
+		if err := s.userRepo.Update(user); err != nil {
+			return err
+		}
+	}
+
+	return s.authService.RevokeAllTokens(user.ID)
+}
+
+// scimUserPatch collects the attributes set by a PATCH request
+type scimUserPatch struct {
+	userName    *string
+	active      *bool
+	displayName *string
+	formatted   *string
+	givenName   *string
+	familyName  *string
+}
+
+// apply records a single attribute value. Without a path the value holds several attributes.
+func (p *scimUserPatch) apply(path string, value json.RawMessage) error {
+	path = stripSCIMSchema(path)
+	switch strings.ToLower(path) {
+	case "":
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(value, &attributes); err != nil {
+			return errors.New("invalid patch value")
+		}
+		for attribute, attributeValue := range attributes {
+			if attribute == "" {
+				continue
+			}
+			if err := p.apply(attribute, attributeValue); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "active":
+		active, err := parseSCIMBool(value)
+		if err != nil {
+			return err
+		}
+		p.active = &active
+		return nil
+	case "name":
+		var name model.SCIMName
+		if err := json.Unmarshal(value, &name); err != nil {
+			return errors.New("invalid patch value")
+		}
+		p.formatted, p.givenName, p.familyName = &name.Formatted, &name.GivenName, &name.FamilyName
+		return nil
+	}
+
+	var target **string
+	switch strings.ToLower(path) {
+	case "username":
+		target = &p.userName
+	case "displayname":
+		target = &p.displayName
+	case "name.formatted":
+		target = &p.formatted
+	case "name.givenname":
+		target = &p.givenName
+	case "name.familyname":
+		target = &p.familyName
+	default:
+		// Attributes this service does not store are ignored
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		return errors.New("invalid patch value")
+	}
+	*target = &text
+	return nil
+}
+
+// name returns the display name to store, preferring a complete name over its parts
+func (p *scimUserPatch) name() string {
+	for _, candidate := range []*string{p.displayName, p.formatted} {
+		if candidate != nil && strings.TrimSpace(*candidate) != "" {
+			return strings.TrimSpace(*candidate)
+		}
+	}
+
+	var parts []string
+	for _, part := range []*string{p.givenName, p.familyName} {
+		if part != nil && strings.TrimSpace(*part) != "" {
+			parts = append(parts, strings.TrimSpace(*part))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseSCIMFilter returns the email searched for by a userName or emails equality filter
+func parseSCIMFilter(filter string) (string, error) {
+	match := scimFilterPattern.FindStringSubmatch(filter)
+	if match == nil {
+		return "", errors.New("invalid filter")
+	}
+
+	switch strings.ToLower(stripSCIMSchema(match[1])) {
+	case "username", "emails", "emails.value":
+	default:
+		return "", errors.New("invalid filter")
+	}
+
+	value, err := strconv.Unquote(match[2])
+	if err != nil {
+		return "", errors.New("invalid filter")
+	}
+	return value, nil
+}
+
+// parseSCIMBool accepts JSON booleans as well as the "True" and "False" strings some directories send
+func parseSCIMBool(value json.RawMessage) (bool, error) {
+	var parsed interface{}
+	if err := json.Unmarshal(value, &parsed); err != nil {
+		return false, errors.New("invalid patch value")
+	}
+
+	switch v := parsed.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(strings.ToLower(v))
+		if err != nil {
+			return false, errors.New("invalid patch value")
+		}
+		return b, nil
+	}
+	return false, errors.New("invalid patch value")
+}
+
+// stripSCIMSchema removes the core User schema prefix from a fully qualified attribute
+func stripSCIMSchema(attribute string) string {
+	prefix := model.SCIMSchemaUser + ":"
+	if len(attribute) >= len(prefix) && strings.EqualFold(attribute[:len(prefix)], prefix) {
+		return attribute[len(prefix):]
+	}
+	return attribute
+}
+
+// isEmailAddress reports whether s is a bare email address
+func isEmailAddress(s string) bool {
+	address, err := mail.ParseAddress(s)
+	return err == nil && address.Address == s
+}
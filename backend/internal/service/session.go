@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+)
+
+// lastSeenResolution limits how often session activity is written to the database
+const lastSeenResolution = time.Minute
+
+// SessionService defines the interface for session management operations
+type SessionService interface {
+	List(userID, currentSessionID string) ([]model.SessionResponse, error)
+	Revoke(userID, sessionID string) error
+	RevokeOthers(userID, currentSessionID string) error
+	Touch(session *model.Session) error
+}
+
+// sessionService implements SessionService interface
+type sessionService struct {
+	sessionRepo      repository.SessionRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(sessionRepo repository.SessionRepository, refreshTokenRepo repository.RefreshTokenRepository) SessionService {
+	return &sessionService{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+	}
+}
+
+// List retrieves the active sessions of a user and marks the current one
+func (s *sessionService) List(userID, currentSessionID string) ([]model.SessionResponse, error) {
+	sessions, err := s.sessionRepo.GetActiveByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = session.ToResponse(currentSessionID)
+	}
+
+	return responses, nil
+}
+
+// Revoke ends a session of a user. Access tokens of the session are rejected
+// from then on and its refresh tokens are revoked.
+func (s *sessionService) Revoke(userID, sessionID string) error {
+	revoked, err := s.sessionRepo.Revoke(userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("session not found")
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(sessionID)
+}
+
+// RevokeOthers ends every session of a user except the current one
+func (s *sessionService) RevokeOthers(userID, currentSessionID string) error {
+	sessionIDs, err := s.sessionRepo.RevokeOthers(userID, currentSessionID)
+	if err != nil {
+		return err
+	}
+
+	for _, sessionID := range sessionIDs {
+		if err := s.refreshTokenRepo.RevokeFamily(sessionID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Touch records that a session was used
+func (s *sessionService) Touch(session *model.Session) error {
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) < lastSeenResolution {
+		return nil
+	}
+	return s.sessionRepo.UpdateLastSeen(session.ID, now)
+}
+
+// truncate shortens a string to at most max bytes without splitting a character
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	for max > 0 && !utf8.RuneStart(value[max]) {
+		max--
+	}
+	return value[:max]
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// TagService defines the interface for tag operations
+type TagService interface {
+	List(userID string) ([]model.Tag, error)
+	Create(userID string, req *model.CreateTagRequest) (*model.Tag, error)
+	Update(userID, tagID string, req *model.UpdateTagRequest) (*model.Tag, error)
+	Delete(userID, tagID string) error
+}
+
+// tagService implements TagService interface
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+// NewTagService creates a new tag service
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{
+		tagRepo: tagRepo,
+	}
+}
+
+// List retrieves the tags of a user in alphabetical order
+func (s *tagService) List(userID string) ([]model.Tag, error) {
+	return s.tagRepo.GetByUserID(userID)
+}
+
+// Create creates a new tag. Tag names are unique per user.
+func (s *tagService) Create(userID string, req *model.CreateTagRequest) (*model.Tag, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkName(userID, name, ""); err != nil {
+		return nil, err
+	}
+
+	color := strings.ToLower(req.Color)
+	if color == "" {
+		color = model.DefaultTagColor
+	}
+
+	tag := &model.Tag{
+		UserID: userID,
+		Name:   name,
+		Color:  color,
+	}
+
+	if err := s.tagRepo.Create(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Update renames or recolors a tag. Todos refer to tags by ID, so they show the change immediately.
+func (s *tagService) Update(userID, tagID string, req *model.UpdateTagRequest) (*model.Tag, error) {
+	tag, err := s.getTag(userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := s.checkName(userID, name, tag.ID); err != nil {
+			return nil, err
+		}
+		tag.Name = name
+	}
+	if req.Color != nil {
+		tag.Color = strings.ToLower(*req.Color)
+	}
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Delete deletes a tag and removes it from every todo
+func (s *tagService) Delete(userID, tagID string) error {
+	if _, err := s.getTag(userID, tagID); err != nil {
+		return err
+	}
+
+	return s.tagRepo.Delete(tagID)
+}
+
+// getTag retrieves a tag owned by the user
+func (s *tagService) getTag(userID, tagID string) (*model.Tag, error) {
+	tag, err := s.tagRepo.GetUserTagByID(userID, tagID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+// checkName rejects empty names and names already used by another of the user's tags
+func (s *tagService) checkName(userID, name, excludeID string) error {
+	if name == "" {
+		return errors.New("tag name is required")
+	}
+
+	exists, err := s.tagRepo.NameExists(userID, name, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("tag already exists")
+	}
+	return nil
+}
@@ -3,9 +3,11 @@ package service
 import (
 	"errors"
 	"math"
+	"time"
 
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
 	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/rrule"
 	"gorm.io/gorm"
 )
 
@@ -17,29 +19,63 @@ type TodoService interface {
 	Update(userID, todoID string, req *model.UpdateTodoRequest) (*model.Todo, error)
 	Delete(userID, todoID string) error
 	ToggleStatus(userID, todoID string) (*model.Todo, error)
+	StopRecurrence(userID, todoID string) (*model.Todo, error)
 }
 
 // todoService implements TodoService interface
 type todoService struct {
-	todoRepo repository.TodoRepository
+	todoRepo     repository.TodoRepository
+	tagRepo      repository.TagRepository
+	projectRepo  repository.ProjectRepository
+	reminderRepo repository.ReminderRepository
 }
 
 // NewTodoService creates a new todo service
-func NewTodoService(todoRepo repository.TodoRepository) TodoService {
+func NewTodoService(todoRepo repository.TodoRepository, tagRepo repository.TagRepository, projectRepo repository.ProjectRepository, reminderRepo repository.ReminderRepository) TodoService {
 	return &todoService{
-		todoRepo: todoRepo,
+		todoRepo:     todoRepo,
+		tagRepo:      tagRepo,
+		projectRepo:  projectRepo,
+		reminderRepo: reminderRepo,
 	}
 }
 
 // Create creates a new todo
 func (s *todoService) Create(userID string, req *model.CreateTodoRequest) (*model.Todo, error) {
+	tags, err := s.resolveTags(userID, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ProjectID != nil && *req.ProjectID != "" {
+		if err := s.checkProject(userID, *req.ProjectID); err != nil {
+			return nil, err
+		}
+	} else {
+		req.ProjectID = nil
+	}
+
+	var recurrence string
+	if req.Recurrence != "" {
+		if recurrence, err = normalizeRecurrence(req.Recurrence, req.DueDate); err != nil {
+			return nil, err
+		}
+	}
+
 	todo := &model.Todo{
-		Title:       req.Title,
-		Description: req.Description,
-		Priority:    req.Priority,
-		Status:      model.StatusPending,
-		UserID:      userID,
-		DueDate:     req.DueDate,
+		Title:        req.Title,
+		Description:  req.Description,
+		Priority:     req.Priority,
+		Status:       model.StatusPending,
+		UserID:       userID,
+		ProjectID:    req.ProjectID,
+		DueDate:      req.DueDate,
+		AutoComplete: req.AutoComplete,
+		Recurrence:   recurrence,
+		Tags:         tags,
+	}
+	if recurrence != "" {
+		todo.RecurrenceStart = req.DueDate
 	}
 
 	if err := s.todoRepo.Create(todo); err != nil {
@@ -71,6 +107,7 @@ func (s *todoService) GetList(userID string, req *model.TodoListRequest) (*model
 	if req.Limit < 1 || req.Limit > 100 {
 		req.Limit = 10
 	}
+	req.Tags = uniqueStrings(req.Tags)
 
 	todos, total, err := s.todoRepo.GetByUserID(userID, req)
 	if err != nil {
@@ -106,6 +143,26 @@ func (s *todoService) Update(userID, todoID string, req *model.UpdateTodoRequest
 		return nil, err
 	}
 
+	wasCompleted := todo.IsCompleted()
+
+	// Resolve tags and the project first so unknown ones leave the todo unchanged
+	var tags []model.Tag
+	if req.TagIDs != nil {
+		if tags, err = s.resolveTags(userID, *req.TagIDs); err != nil {
+			return nil, err
+		}
+	}
+	if req.ProjectID != nil {
+		if *req.ProjectID == "" {
+			todo.ProjectID = nil
+		} else {
+			if err := s.checkProject(userID, *req.ProjectID); err != nil {
+				return nil, err
+			}
+			todo.ProjectID = req.ProjectID
+		}
+	}
+
 	// Update fields if provided
 	if req.Title != nil {
 		todo.Title = *req.Title
@@ -122,11 +179,47 @@ func (s *todoService) Update(userID, todoID string, req *model.UpdateTodoRequest
 	if req.DueDate != nil {
 		todo.DueDate = req.DueDate
 	}
+	if req.AutoComplete != nil {
+		todo.AutoComplete = *req.AutoComplete
+	}
 
-	if err := s.todoRepo.Update(todo); err != nil {
+	// A new rule restarts the series from the current due date
+	if req.Recurrence != nil {
+		if *req.Recurrence == "" {
+			todo.StopRecurrence()
+		} else {
+			recurrence, err := normalizeRecurrence(*req.Recurrence, todo.DueDate)
+			if err != nil {
+				return nil, err
+			}
+			todo.Recurrence = recurrence
+			todo.RecurrenceStart = todo.DueDate
+		}
+	}
+	if req.TagIDs != nil {
+		todo.Tags = tags
+	}
+
+	if err := s.save(todo, wasCompleted); err != nil {
 		return nil, err
 	}
 
+	if req.TagIDs != nil {
+		if err := s.todoRepo.ReplaceTags(todo, tags); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reminders follow the due date
+	if req.DueDate != nil && len(todo.Reminders) > 0 {
+		if err := s.reminderRepo.Reschedule(todo.ID, *todo.DueDate); err != nil {
+			return nil, err
+		}
+		if todo.Reminders, err = s.reminderRepo.GetByTodoID(todo.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	return todo, nil
 }
 
@@ -155,15 +248,134 @@ func (s *todoService) ToggleStatus(userID, todoID string) (*model.Todo, error) {
 	}
 
 	// Toggle status
-	if todo.IsCompleted() {
+	wasCompleted := todo.IsCompleted()
+	if wasCompleted {
 		todo.MarkAsPending()
 	} else {
 		todo.MarkAsCompleted()
 	}
 
+	if err := s.save(todo, wasCompleted); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// StopRecurrence ends the series of a recurring todo. The todo itself is kept.
+func (s *todoService) StopRecurrence(userID, todoID string) (*model.Todo, error) {
+	todo, err := s.GetByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	todo.StopRecurrence()
+
 	if err := s.todoRepo.Update(todo); err != nil {
 		return nil, err
 	}
 
 	return todo, nil
 }
+
+// save updates a todo. Completing an occurrence of a recurring todo also creates the
+// next occurrence, which carries the series on while the completed one stops recurring.
+func (s *todoService) save(todo *model.Todo, wasCompleted bool) error {
+	rule, start, ok := todo.RecurrenceRule()
+	if wasCompleted || !todo.IsCompleted() || !ok {
+		return s.todoRepo.Update(todo)
+	}
+
+	next := nextOccurrence(todo, rule, start)
+	todo.StopRecurrence()
+	if next == nil {
+		return s.todoRepo.Update(todo)
+	}
+
+	return s.todoRepo.CompleteOccurrence(todo, next)
+}
+
+// nextOccurrence builds the occurrence that follows a recurring todo, or returns nil once
+// the series has ended. The checklist is copied with every item reopened, and reminders
+// are scheduled for the new due date.
+func nextOccurrence(todo *model.Todo, rule *rrule.Rule, start time.Time) *model.Todo {
+	dueDate, ok := rule.Next(start, *todo.DueDate)
+	if !ok {
+		return nil
+	}
+
+	items := make([]model.TodoItem, len(todo.Items))
+	for i, item := range todo.Items {
+		items[i] = model.TodoItem{
+			UserID:   item.UserID,
+			Title:    item.Title,
+			Position: item.Position,
+		}
+	}
+
+	reminders := make([]model.Reminder, len(todo.Reminders))
+	for i, reminder := range todo.Reminders {
+		reminders[i] = model.Reminder{
+			UserID:        reminder.UserID,
+			OffsetMinutes: reminder.OffsetMinutes,
+		}
+		reminders[i].Schedule(&dueDate)
+	}
+
+	return &model.Todo{
+		Title:           todo.Title,
+		Description:     todo.Description,
+		Priority:        todo.Priority,
+		Status:          model.StatusPending,
+		UserID:          todo.UserID,
+		ProjectID:       todo.ProjectID,
+		DueDate:         &dueDate,
+		AutoComplete:    todo.AutoComplete,
+		Recurrence:      todo.Recurrence,
+		RecurrenceStart: &start,
+		Items:           items,
+		Tags:            todo.Tags,
+		Reminders:       reminders,
+	}
+}
+
+// normalizeRecurrence validates an RRULE and returns it in canonical form.
+// The due date of a recurring todo is the start of its series, so one is required.
+func normalizeRecurrence(value string, dueDate *time.Time) (string, error) {
+	rule, err := rrule.Parse(value)
+	if err != nil {
+		return "", errors.New("invalid recurrence rule")
+	}
+	if dueDate == nil {
+		return "", errors.New("recurrence requires a due date")
+	}
+	// A rule such as February 30th, or an UNTIL before the due date, never repeats
+	if _, ok := rule.Next(*dueDate, *dueDate); !ok {
+		return "", errors.New("recurrence has no occurrences")
+	}
+	return rule.String(), nil
+}
+
+// resolveTags loads the user's tags with the given IDs. Unknown IDs and tags of other users are rejected.
+func (s *todoService) resolveTags(userID string, tagIDs []string) ([]model.Tag, error) {
+	tagIDs = uniqueStrings(tagIDs)
+	tags, err := s.tagRepo.GetUserTagsByIDs(userID, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(tagIDs) {
+		return nil, errors.New("tag not found")
+	}
+	return tags, nil
+}
+
+// checkProject rejects projects that do not exist or belong to another user
+func (s *todoService) checkProject(userID, projectID string) error {
+	if _, err := s.projectRepo.GetUserProjectByID(userID, projectID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("project not found")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// TodoItemService defines the interface for checklist item operations
+type TodoItemService interface {
+	List(userID, todoID string) ([]model.TodoItem, error)
+	Create(userID, todoID string, req *model.CreateTodoItemRequest) (*model.TodoItem, error)
+	Update(userID, todoID, itemID string, req *model.UpdateTodoItemRequest) (*model.TodoItem, error)
+	Delete(userID, todoID, itemID string) error
+	Reorder(userID, todoID string, req *model.ReorderTodoItemsRequest) ([]model.TodoItem, error)
+}
+
+// todoItemService implements TodoItemService interface
+type todoItemService struct {
+	itemRepo    repository.TodoItemRepository
+	todoService TodoService
+}
+
+// NewTodoItemService creates a new checklist item service
+func NewTodoItemService(itemRepo repository.TodoItemRepository, todoService TodoService) TodoItemService {
+	return &todoItemService{
+		itemRepo:    itemRepo,
+		todoService: todoService,
+	}
+}
+
+// List retrieves the checklist items of a todo in display order
+func (s *todoItemService) List(userID, todoID string) ([]model.TodoItem, error) {
+	todo, err := s.todoService.GetByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	return todo.Items, nil
+}
+
+// Create appends a checklist item to a todo
+func (s *todoItemService) Create(userID, todoID string, req *model.CreateTodoItemRequest) (*model.TodoItem, error) {
+	todo, err := s.todoService.GetByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(todo.Items) >= model.MaxTodoItems {
+		return nil, errors.New("checklist is full")
+	}
+
+	position, err := s.itemRepo.NextPosition(todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &model.TodoItem{
+		TodoID:    todoID,
+		UserID:    userID,
+		Title:     strings.TrimSpace(req.Title),
+		Completed: req.Completed,
+		Position:  position,
+	}
+
+	if err := s.itemRepo.Create(item); err != nil {
+		return nil, err
+	}
+
+	if err := s.syncTodoStatus(userID, todoID); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Update updates a checklist item
+func (s *todoItemService) Update(userID, todoID, itemID string, req *model.UpdateTodoItemRequest) (*model.TodoItem, error) {
+	item, err := s.getItem(userID, todoID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if req.Title != nil {
+		item.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Completed != nil {
+		item.Completed = *req.Completed
+	}
+
+	if err := s.itemRepo.Update(item); err != nil {
+		return nil, err
+	}
+
+	if err := s.syncTodoStatus(userID, todoID); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Delete deletes a checklist item
+func (s *todoItemService) Delete(userID, todoID, itemID string) error {
+	if _, err := s.getItem(userID, todoID, itemID); err != nil {
+		return err
+	}
+
+	if err := s.itemRepo.Delete(itemID); err != nil {
+		return err
+	}
+
+	return s.syncTodoStatus(userID, todoID)
+}
+
+// Reorder sets the order of a todo's checklist. itemIDs must list every item exactly once.
+func (s *todoItemService) Reorder(userID, todoID string, req *model.ReorderTodoItemsRequest) ([]model.TodoItem, error) {
+	todo, err := s.todoService.GetByID(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.ItemIDs) != len(todo.Items) {
+		return nil, errors.New("invalid item order")
+	}
+	remaining := make(map[string]bool, len(todo.Items))
+	for _, item := range todo.Items {
+		remaining[item.ID] = true
+	}
+	for _, itemID := range req.ItemIDs {
+		if !remaining[itemID] {
+			return nil, errors.New("invalid item order")
+		}
+		delete(remaining, itemID)
+	}
+
+	if err := s.itemRepo.Reorder(todoID, req.ItemIDs); err != nil {
+		return nil, err
+	}
+
+	return s.itemRepo.GetByTodoID(todoID)
+}
+
+// getItem retrieves a checklist item of a todo owned by the user
+func (s *todoItemService) getItem(userID, todoID, itemID string) (*model.TodoItem, error) {
+	if _, err := s.todoService.GetByID(userID, todoID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.itemRepo.GetTodoItemByID(todoID, itemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("item not found")
+		}
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// syncTodoStatus completes a todo with auto-complete once every checklist item is done,
+// using ToggleStatus. It never reopens a todo, since the owner may have completed it
+// by hand with items left open.
+func (s *todoItemService) syncTodoStatus(userID, todoID string) error {
+	todo, err := s.todoService.GetByID(userID, todoID)
+	if err != nil {
+		return err
+	}
+
+	if !todo.AutoComplete || len(todo.Items) == 0 || todo.IsCompleted() {
+		return nil
+	}
+
+	if model.NewTodoProgress(todo.Items).IsDone() {
+		_, err = s.todoService.ToggleStatus(userID, todoID)
+	}
+	return err
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/config"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/model"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/repository"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/security"
+	"github.com/nshmdayo/github-copilot-sample/backend/internal/webauthn"
+	"gorm.io/gorm"
+)
+
+// WebAuthnService defines the interface for passkey registration, login and management
+type WebAuthnService interface {
+	BeginRegistration(userID string) (*webauthn.CreationOptions, error)
+	FinishRegistration(userID string, req *model.WebAuthnRegistrationRequest) (*model.WebAuthnCredentialResponse, error)
+	BeginLogin(req *model.WebAuthnLoginBeginRequest) (*webauthn.RequestOptions, error)
+	FinishLogin(req *model.WebAuthnLoginRequest, client model.ClientInfo) (*model.LoginResponse, *model.MFAChallenge, error)
+	ListCredentials(userID string) ([]model.WebAuthnCredentialResponse, error)
+	RenameCredential(userID, id string, req *model.RenameWebAuthnCredentialRequest) error
+	DeleteCredential(userID, id string) error
+}
+
+// webauthnService implements WebAuthnService interface
+type webauthnService struct {
+	relyingParty *webauthn.RelyingParty
+	webauthnRepo repository.WebAuthnRepository
+	userRepo     repository.UserRepository
+	authService  AuthService
+	config       *config.Config
+}
+
+// NewWebAuthnService creates a new WebAuthn service
+func NewWebAuthnService(webauthnRepo repository.WebAuthnRepository, userRepo repository.UserRepository, authService AuthService, config *config.Config) WebAuthnService {
+	relyingParty := webauthn.New(webauthn.Config{
+		RPID:    config.WebAuthn.RPID,
+		RPName:  config.WebAuthn.RPName,
+		Origins: config.WebAuthn.Origins,
+		Timeout: time.Duration(config.WebAuthn.ChallengeMinutes) * time.Minute,
+	})
+
+	return &webauthnService{
+		relyingParty: relyingParty,
+		webauthnRepo: webauthnRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		config:       config,
+	}
+}
+
+// BeginRegistration starts registering a new passkey for the user
+func (s *webauthnService) BeginRegistration(userID string) (*webauthn.CreationOptions, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	credentials, err := s.webauthnRepo.ListCredentials(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.createChallenge(model.WebAuthnCeremonyRegistration, &user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user handle is the user ID so a discoverable credential identifies its account
+	webauthnUser := webauthn.User{
+		ID:          []byte(user.ID),
+		Name:        user.Email,
+		DisplayName: user.Name,
+	}
+	return s.relyingParty.CreationOptions(challenge, webauthnUser, credentialDescriptors(credentials)), nil
+}
+
+// FinishRegistration verifies the authenticator response and stores the new passkey
+func (s *webauthnService) FinishRegistration(userID string, req *model.WebAuthnRegistrationRequest) (*model.WebAuthnCredentialResponse, error) {
+	clientDataJSON, err := webauthn.DecodeBase64URL(req.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, errors.New("invalid passkey response")
+	}
+	attestationObject, err := webauthn.DecodeBase64URL(req.Credential.Response.AttestationObject)
+	if err != nil {
+		return nil, errors.New("invalid passkey response")
+	}
+
+	challenge, challengeValue, err := s.consumeChallenge(clientDataJSON, model.WebAuthnCeremonyRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.UserID == nil || *challenge.UserID != userID {
+		return nil, errors.New("invalid or expired challenge")
+	}
+
+	verified, err := s.relyingParty.VerifyRegistration(challengeValue, clientDataJSON, attestationObject)
+	if err != nil {
+		log.Printf("Passkey registration for user %s failed: %v", userID, err)
+		return nil, errors.New("invalid passkey response")
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(verified.ID)
+	if _, err := s.webauthnRepo.GetCredentialByCredentialID(credentialID); err == nil {
+		return nil, errors.New("passkey already registered")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = "Passkey"
+	}
+
+	credential := &model.WebAuthnCredential{
+		UserID:       userID,
+		CredentialID: credentialID,
+		PublicKey:    verified.PublicKey,
+		SignCount:    int64(verified.SignCount),
+		AAGUID:       hex.EncodeToString(verified.AAGUID),
+		Transports:   strings.Join(req.Credential.Response.Transports, ","),
+		Name:         name,
+	}
+	if err := s.webauthnRepo.CreateCredential(credential); err != nil {
+		return nil, err
+	}
+
+	response := credential.ToResponse()
+	return &response, nil
+}
+
+// BeginLogin starts a passkey login. With an email address only that account's
+// passkeys are offered; without one the authenticator offers its discoverable credentials.
+func (s *webauthnService) BeginLogin(req *model.WebAuthnLoginBeginRequest) (*webauthn.RequestOptions, error) {
+	var allow []webauthn.CredentialDescriptor
+	var userID *string
+
+	if req.Email != "" {
+		user, err := s.userRepo.GetByEmail(req.Email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			credentials, err := s.webauthnRepo.ListCredentials(user.ID)
+			if err != nil {
+				return nil, err
+			}
+			if len(credentials) > 0 {
+				allow = credentialDescriptors(credentials)
+				userID = &user.ID
+			}
+		}
+	}
+
+	challenge, err := s.createChallenge(model.WebAuthnCeremonyLogin, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.relyingParty.RequestOptions(challenge, allow), nil
+}
+
+// FinishLogin verifies a passkey assertion and logs the user in.
+// A passkey that verified the user counts as two factors, so no TOTP code is needed.
+func (s *webauthnService) FinishLogin(req *model.WebAuthnLoginRequest, client model.ClientInfo) (*model.LoginResponse, *model.MFAChallenge, error) {
+	clientDataJSON, err := webauthn.DecodeBase64URL(req.Response.ClientDataJSON)
+	if err != nil {
+		return nil, nil, errors.New("invalid passkey response")
+	}
+	authenticatorData, err := webauthn.DecodeBase64URL(req.Response.AuthenticatorData)
+	if err != nil {
+		return nil, nil, errors.New("invalid passkey response")
+	}
+	signature, err := webauthn.DecodeBase64URL(req.Response.Signature)
+	if err != nil {
+		return nil, nil, errors.New("invalid passkey response")
+	}
+	rawID, err := webauthn.DecodeBase64URL(req.ID)
+	if err != nil {
+		return nil, nil, errors.New("invalid passkey response")
+	}
+
+	challenge, challengeValue, err := s.consumeChallenge(clientDataJSON, model.WebAuthnCeremonyLogin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credential, err := s.webauthnRepo.GetCredentialByCredentialID(base64.RawURLEncoding.EncodeToString(rawID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("invalid passkey")
+		}
+		return nil, nil, err
+	}
+
+	// The passkey must belong to the account the login was started for
+	if challenge.UserID != nil && *challenge.UserID != credential.UserID {
+		return nil, nil, errors.New("invalid passkey")
+	}
+	if req.Response.UserHandle != "" {
+		userHandle, err := webauthn.DecodeBase64URL(req.Response.UserHandle)
+		if err != nil || string(userHandle) != credential.UserID {
+			return nil, nil, errors.New("invalid passkey")
+		}
+	}
+
+	assertion, err := s.relyingParty.VerifyAssertion(challengeValue, credential.PublicKey, uint32(credential.SignCount), clientDataJSON, authenticatorData, signature)
+	if err != nil {
+		log.Printf("Passkey login with credential %s failed: %v", credential.ID, err)
+		return nil, nil, errors.New("invalid passkey")
+	}
+
+	if err := s.webauthnRepo.RecordUse(credential.ID, int64(assertion.SignCount), time.Now()); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(credential.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("invalid passkey")
+		}
+		return nil, nil, err
+	}
+
+	return s.authService.ContinueLogin(user, client, assertion.UserVerified)
+}
+
+// ListCredentials retrieves the passkeys of a user
+func (s *webauthnService) ListCredentials(userID string) ([]model.WebAuthnCredentialResponse, error) {
+	credentials, err := s.webauthnRepo.ListCredentials(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.WebAuthnCredentialResponse, len(credentials))
+	for i := range credentials {
+		responses[i] = credentials[i].ToResponse()
+	}
+
+	return responses, nil
+}
+
+// RenameCredential changes the name of one of the user's passkeys
+func (s *webauthnService) RenameCredential(userID, id string, req *model.RenameWebAuthnCredentialRequest) error {
+	renamed, err := s.webauthnRepo.RenameCredential(userID, id, strings.TrimSpace(req.Name))
+	if err != nil {
+		return err
+	}
+	if !renamed {
+		return errors.New("passkey not found")
+	}
+	return nil
+}
+
+// DeleteCredential removes one of the user's passkeys
+func (s *webauthnService) DeleteCredential(userID, id string) error {
+	deleted, err := s.webauthnRepo.DeleteCredential(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("passkey not found")
+	}
+	return nil
+}
+
+// createChallenge generates and stores the challenge of a new ceremony
+func (s *webauthnService) createChallenge(ceremony model.WebAuthnCeremony, userID *string) (string, error) {
+	challenge, err := security.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if err := s.webauthnRepo.DeleteExpiredChallenges(now); err != nil {
+		log.Printf("Failed to delete expired WebAuthn challenges: %v", err)
+	}
+
+	stored := &model.WebAuthnChallenge{
+		ChallengeHash: security.HashToken(challenge),
+		Ceremony:      ceremony,
+		UserID:        userID,
+		ExpiresAt:     now.Add(time.Duration(s.config.WebAuthn.ChallengeMinutes) * time.Minute),
+	}
+	if err := s.webauthnRepo.CreateChallenge(stored); err != nil {
+		return "", err
+	}
+
+	return challenge, nil
+}
+
+// consumeChallenge finds and invalidates the pending ceremony a response answers.
+// It returns the stored ceremony together with the challenge value.
+func (s *webauthnService) consumeChallenge(clientDataJSON []byte, ceremony model.WebAuthnCeremony) (*model.WebAuthnChallenge, string, error) {
+	challenge, err := webauthn.ClientDataChallenge(clientDataJSON)
+	if err != nil {
+		return nil, "", errors.New("invalid passkey response")
+	}
+
+	stored, err := s.webauthnRepo.ConsumeChallenge(security.HashToken(challenge), ceremony)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errors.New("invalid or expired challenge")
+		}
+		return nil, "", err
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, "", errors.New("invalid or expired challenge")
+	}
+
+	return stored, challenge, nil
+}
+
+// credentialDescriptors lists stored passkeys in the form used by ceremony options
+func credentialDescriptors(credentials []model.WebAuthnCredential) []webauthn.CredentialDescriptor {
+	descriptors := make([]webauthn.CredentialDescriptor, len(credentials))
+	for i, credential := range credentials {
+		descriptors[i] = webauthn.CredentialDescriptor{Type: "public-key", ID: credential.CredentialID}
+		if credential.Transports != "" {
+			descriptors[i].Transports = strings.Split(credential.Transports, ",")
+		}
+	}
+	return descriptors
+}
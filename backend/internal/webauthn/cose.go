@@ -0,0 +1,114 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// COSE algorithm identifiers of the supported credential keys
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+// COSE key type and curve identifiers
+const (
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+)
+
+// COSE key map labels
+const (
+	coseLabelKeyType   = 1
+	coseLabelAlgorithm = 3
+	coseLabelCurve     = -1 // also the RSA modulus
+	coseLabelX         = -2 // also the RSA exponent
+	coseLabelY         = -3
+)
+
+// publicKey is a credential public key decoded from its COSE form
+type publicKey struct {
+	algorithm int64
+	key       crypto.PublicKey
+}
+
+// parsePublicKey decodes a COSE_Key and checks it is a supported, well-formed key
+func parsePublicKey(data []byte) (*publicKey, []byte, error) {
+	item, rest, err := decodeCBOR(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, errors.New("credential public key is not a cose key")
+	}
+
+	keyType, _ := fields[int64(coseLabelKeyType)].(int64)
+	algorithm, _ := fields[int64(coseLabelAlgorithm)].(int64)
+
+	switch {
+	case keyType == coseKeyTypeEC2 && algorithm == AlgES256:
+		curve, _ := fields[int64(coseLabelCurve)].(int64)
+		x, _ := fields[int64(coseLabelX)].([]byte)
+		y, _ := fields[int64(coseLabelY)].([]byte)
+		if curve != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, nil, errors.New("invalid ec2 public key")
+		}
+
+		// Reject points that are not on the curve
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, nil, errors.New("invalid ec2 public key")
+		}
+
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		return &publicKey{algorithm: algorithm, key: key}, rest, nil
+
+	case keyType == coseKeyTypeOKP && algorithm == AlgEdDSA:
+		curve, _ := fields[int64(coseLabelCurve)].(int64)
+		x, _ := fields[int64(coseLabelX)].([]byte)
+		if curve != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, nil, errors.New("invalid okp public key")
+		}
+		return &publicKey{algorithm: algorithm, key: ed25519.PublicKey(x)}, rest, nil
+
+	case keyType == coseKeyTypeRSA && algorithm == AlgRS256:
+		n, _ := fields[int64(coseLabelCurve)].([]byte)
+		e, _ := fields[int64(coseLabelX)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, nil, errors.New("invalid rsa public key")
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		return &publicKey{algorithm: algorithm, key: key}, rest, nil
+	}
+
+	return nil, nil, errors.New("unsupported credential public key")
+}
+
+// verify checks a signature over data made with the credential private key
+func (k *publicKey) verify(data, signature []byte) bool {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
@@ -0,0 +1,399 @@
+package webauthn
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Authenticator data flags
+const (
+	flagUserPresent      = 0x01
+	flagUserVerified     = 0x04
+	flagAttestedCredData = 0x40
+	flagExtensionData    = 0x80
+)
+
+// maxCredentialIDLength is the largest credential ID the spec allows
+const maxCredentialIDLength = 1023
+
+// Config holds the settings of a WebAuthn relying party
+type Config struct {
+	RPID    string
+	RPName  string
+	Origins []string
+	Timeout time.Duration
+}
+
+// User identifies the account a credential is registered for
+type User struct {
+	ID          []byte
+	Name        string
+	DisplayName string
+}
+
+// CredentialDescriptor identifies a credential in ceremony options
+type CredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// RelyingPartyEntity describes the relying party to the authenticator
+type RelyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserEntity describes the user account to the authenticator
+type UserEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// CredentialParameter names an acceptable credential key algorithm
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+// AuthenticatorSelection states the authenticator features the relying party asks for
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// CreationOptions are the options for navigator.credentials.create in their JSON form
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     RelyingPartyEntity     `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions are the options for navigator.credentials.get in their JSON form
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// Credential is a newly registered credential whose attestation has been verified
+type Credential struct {
+	ID           []byte
+	PublicKey    []byte
+	SignCount    uint32
+	AAGUID       []byte
+	UserVerified bool
+}
+
+// Assertion is the result of a verified authentication ceremony
+type Assertion struct {
+	SignCount    uint32
+	UserVerified bool
+}
+
+// clientData holds the fields of the client data JSON that are checked
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// authenticatorData is the parsed binary authenticator data
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    *publicKey
+	rawPublicKey []byte
+}
+
+// RelyingParty runs the server side of WebAuthn registration and authentication ceremonies.
+// Attestation is not requested, so registered authenticators are not checked against a
+// list of trusted models.
+type RelyingParty struct {
+	config Config
+}
+
+// New creates a new relying party
+func New(config Config) *RelyingParty {
+	return &RelyingParty{config: config}
+}
+
+// CreationOptions returns the options for registering a credential.
+// Credentials in exclude are already registered and will not be created again.
+func (rp *RelyingParty) CreationOptions(challenge string, user User, exclude []CredentialDescriptor) *CreationOptions {
+	return &CreationOptions{
+		Challenge: challenge,
+		RP:        RelyingPartyEntity{ID: rp.config.RPID, Name: rp.config.RPName},
+		User: UserEntity{
+			ID:          base64.RawURLEncoding.EncodeToString(user.ID),
+			Name:        user.Name,
+			DisplayName: user.DisplayName,
+		},
+		PubKeyCredParams: []CredentialParameter{
+			{Type: "public-key", Alg: AlgES256},
+			{Type: "public-key", Alg: AlgEdDSA},
+			{Type: "public-key", Alg: AlgRS256},
+		},
+		Timeout:            rp.config.Timeout.Milliseconds(),
+		ExcludeCredentials: nonNil(exclude),
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: "preferred",
+		},
+		Attestation: "none",
+	}
+}
+
+// RequestOptions returns the options for authenticating.
+// An empty allow list lets the user pick any discoverable credential.
+func (rp *RelyingParty) RequestOptions(challenge string, allow []CredentialDescriptor) *RequestOptions {
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          rp.config.Timeout.Milliseconds(),
+		RPID:             rp.config.RPID,
+		AllowCredentials: nonNil(allow),
+		UserVerification: "preferred",
+	}
+}
+
+// ClientDataChallenge returns the challenge in client data JSON without verifying anything.
+// It is used to look up the pending ceremony before the response is verified.
+func ClientDataChallenge(clientDataJSON []byte) (string, error) {
+	var data clientData
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil {
+		return "", errors.New("invalid client data")
+	}
+	if data.Challenge == "" {
+		return "", errors.New("client data has no challenge")
+	}
+	return data.Challenge, nil
+}
+
+// DecodeBase64URL decodes a base64url value with or without padding
+func DecodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// VerifyRegistration verifies the response of a registration ceremony started with challenge
+func (rp *RelyingParty) VerifyRegistration(challenge string, clientDataJSON, attestationObject []byte) (*Credential, error) {
+	if err := rp.verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	item, rest, err := decodeCBOR(attestationObject)
+	if err != nil || len(rest) != 0 {
+		return nil, errors.New("invalid attestation object")
+	}
+	attestation, ok := item.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("invalid attestation object")
+	}
+	format, _ := attestation["fmt"].(string)
+	statement, _ := attestation["attStmt"].(map[interface{}]interface{})
+	rawAuthData, _ := attestation["authData"].([]byte)
+	if format == "" || statement == nil || rawAuthData == nil {
+		return nil, errors.New("invalid attestation object")
+	}
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+	if authData.publicKey == nil {
+		return nil, errors.New("attestation has no credential data")
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := verifyAttestationStatement(format, statement, authData, rawAuthData, clientDataHash[:]); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:           authData.credentialID,
+		PublicKey:    authData.rawPublicKey,
+		SignCount:    authData.signCount,
+		AAGUID:       authData.aaguid,
+		UserVerified: authData.flags&flagUserVerified != 0,
+	}, nil
+}
+
+// VerifyAssertion verifies the response of an authentication ceremony started with challenge
+// against a stored credential public key and signature counter
+func (rp *RelyingParty) VerifyAssertion(challenge string, credentialPublicKey []byte, storedSignCount uint32, clientDataJSON, rawAuthData, signature []byte) (*Assertion, error) {
+	if err := rp.verifyClientData(clientDataJSON, "webauthn.get", challenge); err != nil {
+		return nil, err
+	}
+
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := rp.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	key, _, err := parsePublicKey(credentialPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), rawAuthData...), clientDataHash[:]...)
+	if !key.verify(signed, signature) {
+		return nil, errors.New("invalid assertion signature")
+	}
+
+	// A counter that does not move forward indicates a cloned authenticator
+	if (authData.signCount != 0 || storedSignCount != 0) && authData.signCount <= storedSignCount {
+		return nil, errors.New("signature counter did not increase")
+	}
+
+	return &Assertion{
+		SignCount:    authData.signCount,
+		UserVerified: authData.flags&flagUserVerified != 0,
+	}, nil
+}
+
+func (rp *RelyingParty) verifyClientData(clientDataJSON []byte, ceremony, challenge string) error {
+	var data clientData
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil {
+		return errors.New("invalid client data")
+	}
+
+	if data.Type != ceremony {
+		return fmt.Errorf("unexpected client data type %q", data.Type)
+	}
+	if subtle.ConstantTimeCompare([]byte(data.Challenge), []byte(challenge)) != 1 {
+		return errors.New("challenge mismatch")
+	}
+	if !slices.Contains(rp.config.Origins, data.Origin) {
+		return fmt.Errorf("origin %q is not allowed", data.Origin)
+	}
+	if data.CrossOrigin {
+		return errors.New("cross-origin ceremonies are not allowed")
+	}
+
+	return nil
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(authData *authenticatorData) error {
+	rpIDHash := sha256.Sum256([]byte(rp.config.RPID))
+	if subtle.ConstantTimeCompare(authData.rpIDHash, rpIDHash[:]) != 1 {
+		return errors.New("relying party id mismatch")
+	}
+	if authData.flags&flagUserPresent == 0 {
+		return errors.New("user not present")
+	}
+	return nil
+}
+
+// parseAuthenticatorData parses the binary authenticator data structure
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, errors.New("authenticator data too short")
+	}
+
+	authData := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[37:]
+
+	if authData.flags&flagAttestedCredData != 0 {
+		if len(rest) < 18 {
+			return nil, errors.New("invalid attested credential data")
+		}
+		authData.aaguid = rest[:16]
+		idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLength == 0 || idLength > maxCredentialIDLength || len(rest) < idLength {
+			return nil, errors.New("invalid credential id")
+		}
+		authData.credentialID = rest[:idLength]
+		rest = rest[idLength:]
+
+		key, afterKey, err := parsePublicKey(rest)
+		if err != nil {
+			return nil, err
+		}
+		authData.publicKey = key
+		authData.rawPublicKey = rest[:len(rest)-len(afterKey)]
+		rest = afterKey
+	}
+
+	if authData.flags&flagExtensionData != 0 {
+		var err error
+		if _, rest, err = decodeCBOR(rest); err != nil {
+			return nil, errors.New("invalid extension data")
+		}
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.New("unexpected trailing authenticator data")
+	}
+
+	return authData, nil
+}
+
+// verifyAttestationStatement checks the attestation statement of a new credential.
+// No attestation is requested, so only "none" and self attestation are verified;
+// packed statements chained to an attestation certificate are accepted without a trust
+// decision, and other formats are rejected.
+func verifyAttestationStatement(format string, statement map[interface{}]interface{}, authData *authenticatorData, rawAuthData, clientDataHash []byte) error {
+	switch format {
+	case "none":
+		if len(statement) != 0 {
+			return errors.New("invalid none attestation statement")
+		}
+		return nil
+	case "packed":
+		if _, hasCertificate := statement["x5c"]; hasCertificate {
+			return nil
+		}
+
+		algorithm, _ := statement["alg"].(int64)
+		signature, _ := statement["sig"].([]byte)
+		if algorithm != authData.publicKey.algorithm || signature == nil {
+			return errors.New("invalid packed attestation statement")
+		}
+
+		signed := append(append([]byte(nil), rawAuthData...), clientDataHash...)
+		if !authData.publicKey.verify(signed, signature) {
+			return errors.New("invalid self attestation signature")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported attestation format %q", format)
+}
+
+func nonNil(descriptors []CredentialDescriptor) []CredentialDescriptor {
+	if descriptors == nil {
+		return []CredentialDescriptor{}
+	}
+	return descriptors
+}
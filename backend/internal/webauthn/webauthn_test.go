@@ -0,0 +1,557 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testRPID      = "example.com"
+	testOrigin    = "https://example.com"
+	testChallenge = "c2VydmVyLWNoYWxsZW5nZQ"
+)
+
+func newTestRelyingParty() *RelyingParty {
+	return New(Config{
+		RPID:    testRPID,
+		RPName:  "Example",
+		Origins: []string{testOrigin},
+		Timeout: time.Minute,
+	})
+}
+
+// cborMap is a CBOR map whose entries are encoded in order
+type cborMap [][2]interface{}
+
+// encodeCBOR encodes the subset of CBOR produced by authenticators
+func encodeCBOR(value interface{}) []byte {
+	switch v := value.(type) {
+	case int:
+		if v >= 0 {
+			return cborHead(0, uint64(v))
+		}
+		return cborHead(1, uint64(-1-v))
+	case []byte:
+		return append(cborHead(2, uint64(len(v))), v...)
+	case string:
+		return append(cborHead(3, uint64(len(v))), v...)
+	case bool:
+		if v {
+			return []byte{0xf5}
+		}
+		return []byte{0xf4}
+	case cborMap:
+		out := cborHead(5, uint64(len(v)))
+		for _, entry := range v {
+			out = append(out, encodeCBOR(entry[0])...)
+			out = append(out, encodeCBOR(entry[1])...)
+		}
+		return out
+	}
+	panic("unsupported cbor value")
+}
+
+func cborHead(major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n <= 0xff:
+		return []byte{major<<5 | 24, byte(n)}
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(n))
+	}
+}
+
+// softAuthenticator is a software authenticator holding a single credential
+type softAuthenticator struct {
+	rpID         string
+	credentialID []byte
+	signer       crypto.Signer
+	signCount    uint32
+	flags        byte
+}
+
+func newES256Authenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &softAuthenticator{rpID: testRPID, credentialID: []byte("es256-credential"), signer: key, flags: flagUserPresent | flagUserVerified}
+}
+
+func newEdDSAAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &softAuthenticator{rpID: testRPID, credentialID: []byte("eddsa-credential"), signer: key, flags: flagUserPresent}
+}
+
+// coseKey returns the credential public key in COSE form
+func (a *softAuthenticator) coseKey() []byte {
+	switch key := a.signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		point, err := key.ECDH()
+		if err != nil {
+			panic(err)
+		}
+		raw := point.Bytes()
+		return encodeCBOR(cborMap{
+			{coseLabelKeyType, coseKeyTypeEC2},
+			{coseLabelAlgorithm, AlgES256},
+			{coseLabelCurve, coseCurveP256},
+			{coseLabelX, raw[1:33]},
+			{coseLabelY, raw[33:]},
+		})
+	case ed25519.PublicKey:
+		return encodeCBOR(cborMap{
+			{coseLabelKeyType, coseKeyTypeOKP},
+			{coseLabelAlgorithm, AlgEdDSA},
+			{coseLabelCurve, coseCurveEd25519},
+			{coseLabelX, []byte(key)},
+		})
+	}
+	panic("unsupported key")
+}
+
+func (a *softAuthenticator) algorithm() int {
+	if _, ok := a.signer.(ed25519.PrivateKey); ok {
+		return AlgEdDSA
+	}
+	return AlgES256
+}
+
+// authenticatorData builds authenticator data, with attested credential data when attested is set
+func (a *softAuthenticator) authenticatorData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	flags := a.flags
+	if attested {
+		flags |= flagAttestedCredData
+	}
+
+	data := append([]byte(nil), rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.coseKey()...)
+	}
+	return data
+}
+
+func (a *softAuthenticator) sign(authData, clientDataJSON []byte) []byte {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), authData...), clientDataHash[:]...)
+
+	var signature []byte
+	var err error
+	if _, ok := a.signer.(ed25519.PrivateKey); ok {
+		signature, err = a.signer.Sign(rand.Reader, signed, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(signed)
+		signature, err = a.signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return signature
+}
+
+// register returns the client data JSON and attestation object of a registration
+func (a *softAuthenticator) register(format string, clientDataJSON []byte) []byte {
+	authData := a.authenticatorData(true)
+
+	statement := cborMap{}
+	if format == "packed" {
+		statement = cborMap{
+			{"alg", a.algorithm()},
+			{"sig", a.sign(authData, clientDataJSON)},
+		}
+	}
+
+	return encodeCBOR(cborMap{
+		{"fmt", format},
+		{"attStmt", statement},
+		{"authData", authData},
+	})
+}
+
+// assert returns the authenticator data and signature of an assertion
+func (a *softAuthenticator) assert(clientDataJSON []byte) ([]byte, []byte) {
+	a.signCount++
+	authData := a.authenticatorData(false)
+	return authData, a.sign(authData, clientDataJSON)
+}
+
+func clientDataJSON(ceremony, challenge, origin string) []byte {
+	data, err := json.Marshal(clientData{Type: ceremony, Challenge: challenge, Origin: origin})
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func TestVerifyRegistration(t *testing.T) {
+	rp := newTestRelyingParty()
+
+	tests := []struct {
+		name          string
+		authenticator func(*testing.T) *softAuthenticator
+		format        string
+		userVerified  bool
+	}{
+		{name: "es256 none", authenticator: newES256Authenticator, format: "none", userVerified: true},
+		{name: "es256 packed self", authenticator: newES256Authenticator, format: "packed", userVerified: true},
+		{name: "eddsa packed self", authenticator: newEdDSAAuthenticator, format: "packed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := tt.authenticator(t)
+			clientData := clientDataJSON("webauthn.create", testChallenge, testOrigin)
+
+			credential, err := rp.VerifyRegistration(testChallenge, clientData, authenticator.register(tt.format, clientData))
+			if err != nil {
+				t.Fatalf("VerifyRegistration() error = %v", err)
+			}
+			if string(credential.ID) != string(authenticator.credentialID) {
+				t.Errorf("credential ID = %q, want %q", credential.ID, authenticator.credentialID)
+			}
+			if string(credential.PublicKey) != string(authenticator.coseKey()) {
+				t.Error("credential public key does not match the authenticator key")
+			}
+			if credential.UserVerified != tt.userVerified {
+				t.Errorf("UserVerified = %v, want %v", credential.UserVerified, tt.userVerified)
+			}
+		})
+	}
+}
+
+func TestVerifyRegistrationRejects(t *testing.T) {
+	rp := newTestRelyingParty()
+
+	tests := []struct {
+		name   string
+		modify func(a *softAuthenticator, clientData *[]byte, attestation *[]byte)
+		want   string
+	}{
+		{
+			name: "bad origin",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				*clientData = clientDataJSON("webauthn.create", testChallenge, "https://evil.example")
+				*attestation = a.register("none", *clientData)
+			},
+			want: "origin",
+		},
+		{
+			name: "bad challenge",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				*clientData = clientDataJSON("webauthn.create", "b3RoZXI", testOrigin)
+				*attestation = a.register("none", *clientData)
+			},
+			want: "challenge mismatch",
+		},
+		{
+			name: "assertion client data",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				*clientData = clientDataJSON("webauthn.get", testChallenge, testOrigin)
+				*attestation = a.register("none", *clientData)
+			},
+			want: "unexpected client data type",
+		},
+		{
+			name: "wrong rp id hash",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				a.rpID = "evil.example"
+				*attestation = a.register("none", *clientData)
+			},
+			want: "relying party id mismatch",
+		},
+		{
+			name: "user not present",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				a.flags = flagUserVerified
+				*attestation = a.register("none", *clientData)
+			},
+			want: "user not present",
+		},
+		{
+			name: "unsupported format",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				*attestation = a.register("fido-u2f", *clientData)
+			},
+			want: "unsupported attestation format",
+		},
+		{
+			name: "bad self attestation signature",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				other := clientDataJSON("webauthn.create", "b3RoZXI", testOrigin)
+				*attestation = a.register("packed", other)
+			},
+			want: "invalid self attestation signature",
+		},
+		{
+			name: "truncated attestation object",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				*attestation = (*attestation)[:len(*attestation)-10]
+			},
+			want: "invalid attestation object",
+		},
+		{
+			name: "trailing bytes",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				*attestation = append(*attestation, 0x00)
+			},
+			want: "invalid attestation object",
+		},
+		{
+			name: "not a map",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				*attestation = encodeCBOR("none")
+			},
+			want: "invalid attestation object",
+		},
+		{
+			name: "malformed client data",
+			modify: func(a *softAuthenticator, clientData *[]byte, attestation *[]byte) {
+				*clientData = []byte("{")
+			},
+			want: "invalid client data",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := newES256Authenticator(t)
+			clientData := clientDataJSON("webauthn.create", testChallenge, testOrigin)
+			attestation := authenticator.register("none", clientData)
+			tt.modify(authenticator, &clientData, &attestation)
+
+			_, err := rp.VerifyRegistration(testChallenge, clientData, attestation)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("VerifyRegistration() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAssertion(t *testing.T) {
+	rp := newTestRelyingParty()
+
+	for _, newAuthenticator := range []func(*testing.T) *softAuthenticator{newES256Authenticator, newEdDSAAuthenticator} {
+		authenticator := newAuthenticator(t)
+		storedCount := uint32(0)
+
+		// Consecutive logins move the counter forward
+		for i := 0; i < 2; i++ {
+			clientData := clientDataJSON("webauthn.get", testChallenge, testOrigin)
+			authData, signature := authenticator.assert(clientData)
+
+			assertion, err := rp.VerifyAssertion(testChallenge, authenticator.coseKey(), storedCount, clientData, authData, signature)
+			if err != nil {
+				t.Fatalf("VerifyAssertion() error = %v", err)
+			}
+			if assertion.SignCount != authenticator.signCount {
+				t.Errorf("SignCount = %d, want %d", assertion.SignCount, authenticator.signCount)
+			}
+			storedCount = assertion.SignCount
+		}
+	}
+}
+
+func TestVerifyAssertionZeroCounter(t *testing.T) {
+	rp := newTestRelyingParty()
+	authenticator := newEdDSAAuthenticator(t)
+
+	// Authenticators without a counter always report zero
+	clientData := clientDataJSON("webauthn.get", testChallenge, testOrigin)
+	authData := authenticator.authenticatorData(false)
+	signature := authenticator.sign(authData, clientData)
+
+	if _, err := rp.VerifyAssertion(testChallenge, authenticator.coseKey(), 0, clientData, authData, signature); err != nil {
+		t.Fatalf("VerifyAssertion() error = %v", err)
+	}
+}
+
+func TestVerifyAssertionRejects(t *testing.T) {
+	rp := newTestRelyingParty()
+
+	tests := []struct {
+		name        string
+		storedCount uint32
+		modify      func(a *softAuthenticator, clientData *[]byte, authData, signature *[]byte)
+		want        string
+	}{
+		{
+			name: "bad origin",
+			modify: func(a *softAuthenticator, clientData *[]byte, authData, signature *[]byte) {
+				*clientData = clientDataJSON("webauthn.get", testChallenge, "https://evil.example")
+				*authData, *signature = a.assert(*clientData)
+			},
+			want: "origin",
+		},
+		{
+			name: "bad challenge",
+			modify: func(a *softAuthenticator, clientData *[]byte, authData, signature *[]byte) {
+				*clientData = clientDataJSON("webauthn.get", "b3RoZXI", testOrigin)
+				*authData, *signature = a.assert(*clientData)
+			},
+			want: "challenge mismatch",
+		},
+		{
+			name: "registration client data",
+			modify: func(a *softAuthenticator, clientData *[]byte, authData, signature *[]byte) {
+				*clientData = clientDataJSON("webauthn.create", testChallenge, testOrigin)
+				*authData, *signature = a.assert(*clientData)
+			},
+			want: "unexpected client data type",
+		},
+		{
+			name: "wrong rp id hash",
+			modify: func(a *softAuthenticator, clientData *[]byte, authData, signature *[]byte) {
+				a.rpID = "evil.example"
+				*authData, *signature = a.assert(*clientData)
+			},
+			want: "relying party id mismatch",
+		},
+		{
+			name: "user not present",
+			modify: func(a *softAuthenticator, clientData *[]byte, authData, signature *[]byte) {
+				a.flags = 0
+				*authData, *signature = a.assert(*clientData)
+			},
+			want: "user not present",
+		},
+		{
+			name:        "counter regression",
+			storedCount: 5,
+			modify: func(a *softAuthenticator, clientData *[]byte, authData, signature *[]byte) {
+				a.signCount = 2
+				*authData, *signature = a.assert(*clientData)
+			},
+			want: "signature counter did not increase",
+		},
+		{
+			name:        "counter replay",
+			storedCount: 1,
+			want:        "signature counter did not increase",
+		},
+		{
+			name: "signature over other data",
+			modify: func(a *softAuthenticator, clientData *[]byte, authData, signature *[]byte) {
+				_, *signature = a.assert(clientDataJSON("webauthn.get", "b3RoZXI", testOrigin))
+			},
+			want: "invalid assertion signature",
+		},
+		{
+			name: "truncated authenticator data",
+			modify: func(a *softAuthenticator, clientData *[]byte, authData, signature *[]byte) {
+				*authData = (*authData)[:36]
+			},
+			want: "authenticator data too short",
+		},
+		{
+			name: "trailing authenticator data",
+			modify: func(a *softAuthenticator, clientData *[]byte, authData, signature *[]byte) {
+				*authData = append(*authData, 0x00)
+			},
+			want: "unexpected trailing authenticator data",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := newES256Authenticator(t)
+			clientData := clientDataJSON("webauthn.get", testChallenge, testOrigin)
+			authData, signature := authenticator.assert(clientData)
+			if tt.modify != nil {
+				tt.modify(authenticator, &clientData, &authData, &signature)
+			}
+
+			_, err := rp.VerifyAssertion(testChallenge, authenticator.coseKey(), tt.storedCount, clientData, authData, signature)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("VerifyAssertion() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAssertionRejectsMalformedPublicKey(t *testing.T) {
+	rp := newTestRelyingParty()
+	authenticator := newES256Authenticator(t)
+	clientData := clientDataJSON("webauthn.get", testChallenge, testOrigin)
+	authData, signature := authenticator.assert(clientData)
+
+	key := authenticator.coseKey()
+	for _, malformed := range [][]byte{nil, key[:len(key)-1], encodeCBOR("key")} {
+		if _, err := rp.VerifyAssertion(testChallenge, malformed, 0, clientData, authData, signature); err == nil {
+			t.Errorf("VerifyAssertion() accepted public key %x", malformed)
+		}
+	}
+}
+
+func TestDecodeCBORRejectsMalformed(t *testing.T) {
+	deep := make([]byte, 0, maxCBORDepth+2)
+	for i := 0; i < maxCBORDepth+2; i++ {
+		deep = append(deep, 0x81) // array of one item
+	}
+	deep = append(deep, 0x00)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "truncated argument", data: []byte{0x19, 0x01}},
+		{name: "truncated byte string", data: []byte{0x45, 'a', 'b'}},
+		{name: "truncated map", data: []byte{0xa1, 0x01}},
+		{name: "oversized array", data: []byte{0x9a, 0xff, 0xff, 0xff, 0xff}},
+		{name: "indefinite length", data: []byte{0x5f, 0x41, 'a', 0xff}},
+		{name: "reserved argument", data: []byte{0x1c}},
+		{name: "duplicate map key", data: []byte{0xa2, 0x01, 0x00, 0x01, 0x00}},
+		{name: "byte string map key", data: []byte{0xa1, 0x41, 'a', 0x00}},
+		{name: "too deep", data: deep},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := decodeCBOR(tt.data); err == nil {
+				t.Fatalf("decodeCBOR(%x) succeeded", tt.data)
+			}
+		})
+	}
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	item, rest, err := decodeCBOR(append(encodeCBOR(cborMap{
+		{"fmt", "none"},
+		{-7, []byte{1, 2}},
+		{"big", 70000},
+	}), 0xf5))
+	if err != nil {
+		t.Fatalf("decodeCBOR() error = %v", err)
+	}
+	if len(rest) != 1 || rest[0] != 0xf5 {
+		t.Errorf("rest = %x, want f5", rest)
+	}
+
+	fields := item.(map[interface{}]interface{})
+	if fields["fmt"] != "none" || fields["big"] != int64(70000) || string(fields[int64(-7)].([]byte)) != "\x01\x02" {
+		t.Errorf("decoded map = %v", fields)
+	}
+}
@@ -90,6 +90,7 @@ class ApiClient {
   private removeToken(): void {
     if (typeof window === 'undefined') return;
     localStorage.removeItem('auth_token');
+    localStorage.removeItem('refresh_token');
   }
 
   // GET request
@@ -136,6 +137,18 @@ class ApiClient {
     localStorage.setItem('auth_token', token);
   }
 
+  // Get refresh token
+  getRefreshToken(): string | null {
+    if (typeof window === 'undefined') return null;
+    return localStorage.getItem('refresh_token');
+  }
+
+  // Set refresh token
+  setRefreshToken(token: string): void {
+    if (typeof window === 'undefined') return;
+    localStorage.setItem('refresh_token', token);
+  }
+
   // Remove token
   clearToken(): void {
     this.removeToken();
@@ -22,6 +22,9 @@ export class AuthAPI {
     if (response.token) {
       apiClient.setToken(response.token);
     }
+    if (response.refresh_token) {
+      apiClient.setRefreshToken(response.refresh_token);
+    }
   
     return response;
   }
@@ -42,7 +45,19 @@ export class AuthAPI {
 
   // Token refresh
   static async refreshToken(): Promise<AuthResponse> {
-    return apiClient.post<AuthResponse>('/auth/refresh');
+    const response = await apiClient.post<AuthResponse>('/auth/refresh', {
+      refresh_token: apiClient.getRefreshToken(),
+    });
+
+    // Refresh tokens are rotated on every use
+    if (response.token) {
+      apiClient.setToken(response.token);
+    }
+    if (response.refresh_token) {
+      apiClient.setRefreshToken(response.refresh_token);
+    }
+
+    return response;
   }
 
   // Get current user information
@@ -18,6 +18,7 @@ export interface RegisterRequest extends LoginRequest {
 export interface AuthResponse {
   user: User;
   token: string;
+  refresh_token?: string;
 }
 
 // Authentication state